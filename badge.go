@@ -0,0 +1,166 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// BadgeColor is the color of a badge for coverage ratios of at least MinCoverageRatio
+type BadgeColor struct {
+	MinCoverageRatio float64
+	// Color is a named color, such as "brightgreen", or a hex color such as "#4c1"
+	Color string
+}
+
+// BadgeOptions configure the label and colors of a coverage badge
+type BadgeOptions struct {
+	// Label is the text on the left of the badge, or "codeowners" if empty
+	Label string
+	// Colors are the colors for each band of coverage ratio, or DefaultBadgeColors if empty. A ratio below
+	// every band uses the color of the lowest band.
+	Colors []BadgeColor
+}
+
+// DefaultBadgeColors are the colors of a badge when BadgeOptions do not specify any
+var DefaultBadgeColors = []BadgeColor{
+	{MinCoverageRatio: 0.9, Color: "brightgreen"},
+	{MinCoverageRatio: 0.75, Color: "yellow"},
+	{MinCoverageRatio: 0.5, Color: "orange"},
+	{MinCoverageRatio: 0, Color: "red"},
+}
+
+// badgeNamedColors are the hex values of the named colors supported by Shields
+var badgeNamedColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"lightgrey":   "#9f9f9f",
+	"grey":        "#555",
+}
+
+// shieldsEndpoint is the JSON schema read by the Shields endpoint badge
+// see: https://shields.io/endpoint
+type shieldsEndpoint struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// BadgeSVG renders a standalone SVG badge showing the coverage ratio, in the flat style of Shields
+func (r *Report) BadgeSVG(options BadgeOptions) string {
+	label := options.label()
+	message := formatPercent(r.CoverageRatio)
+	color := options.color(r.CoverageRatio)
+	if hex, ok := badgeNamedColors[color]; ok {
+		color = hex
+	}
+
+	labelWidth := badgeTextWidth(label) + 10
+	messageWidth := badgeTextWidth(message) + 10
+	width := labelWidth + messageWidth
+	title := html.EscapeString(label + ": " + message)
+	label = html.EscapeString(label)
+	message = html.EscapeString(message)
+	color = html.EscapeString(color)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s">`+"\n", width, title)
+	fmt.Fprintf(&b, "<title>%s</title>\n", title)
+	b.WriteString(`<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>` + "\n")
+	fmt.Fprintf(&b, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`+"\n", width)
+	fmt.Fprintf(&b, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`+"\n",
+		labelWidth, labelWidth, messageWidth, color, width)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">` + "\n")
+	for _, text := range []struct {
+		x     float64
+		value string
+	}{{float64(labelWidth) / 2, label}, {float64(labelWidth) + float64(messageWidth)/2, message}} {
+		fmt.Fprintf(&b, `<text x="%g" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%g" y="14">%s</text>`+"\n", text.x, text.value, text.x, text.value)
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// BadgeEndpoint renders the coverage ratio as JSON for a Shields endpoint badge
+func (r *Report) BadgeEndpoint(options BadgeOptions) (string, error) {
+	bytes, err := json.Marshal(shieldsEndpoint{
+		SchemaVersion: 1,
+		Label:         options.label(),
+		Message:       formatPercent(r.CoverageRatio),
+		Color:         options.color(r.CoverageRatio),
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// NewBadgeFormatter creates a Formatter that writes the SVG badge of BadgeSVG with the given options
+func NewBadgeFormatter(options BadgeOptions) Formatter {
+	return stringFormatter(func(r *Report) (string, error) {
+		return r.BadgeSVG(options), nil
+	})
+}
+
+// NewBadgeEndpointFormatter creates a Formatter that writes the Shields endpoint JSON of BadgeEndpoint with the
+// given options, followed by a newline
+func NewBadgeEndpointFormatter(options BadgeOptions) Formatter {
+	return stringFormatter(func(r *Report) (string, error) {
+		endpoint, err := r.BadgeEndpoint(options)
+		if err != nil {
+			return "", err
+		}
+		return endpoint + "\n", nil
+	})
+}
+
+// label returns the label of the badge
+func (o BadgeOptions) label() string {
+	if o.Label == "" {
+		return "codeowners"
+	}
+	return o.Label
+}
+
+// color returns the color of the highest band the coverage ratio reaches
+func (o BadgeOptions) color(ratio float64) string {
+	colors := append([]BadgeColor{}, o.Colors...)
+	if len(colors) == 0 {
+		colors = append(colors, DefaultBadgeColors...)
+	}
+	sort.SliceStable(colors, func(i, j int) bool {
+		return colors[i].MinCoverageRatio > colors[j].MinCoverageRatio
+	})
+	for _, color := range colors {
+		if ratio >= color.MinCoverageRatio {
+			return color.Color
+		}
+	}
+	return colors[len(colors)-1].Color
+}
+
+// badgeTextWidth approximates the width in pixels of text in 11px Verdana, since fonts are not available offline
+func badgeTextWidth(text string) int {
+	var width float64
+	for _, ch := range text {
+		switch {
+		case strings.ContainsRune("ijlI.,:;!|' ", ch):
+			width += 3.5
+		case strings.ContainsRune("mwMW%", ch):
+			width += 10.5
+		case ch >= 'A' && ch <= 'Z':
+			width += 7.5
+		default:
+			width += 7
+		}
+	}
+	return int(width + 0.5)
+}
@@ -0,0 +1,55 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestBadgeColor(t *testing.T) {
+	options := BadgeOptions{Colors: []BadgeColor{
+		{MinCoverageRatio: 0.5, Color: "yellow"},
+		{MinCoverageRatio: 0.8, Color: "green"},
+		{MinCoverageRatio: 0.2, Color: "red"},
+	}}
+	for ratio, expected := range map[float64]string{1: "green", 0.8: "green", 0.6: "yellow", 0.2: "red", 0.1: "red"} {
+		if color := options.color(ratio); color != expected {
+			t.Errorf("expected ratio %g to be colored %s, but it was %s", ratio, expected, color)
+		}
+	}
+	if color := (BadgeOptions{}).color(0.95); color != "brightgreen" {
+		t.Errorf("expected the default colors to be used, but the color was %s", color)
+	}
+}
+
+func TestBadgeSVG(t *testing.T) {
+	report := Report{CoverageRatio: 0.5}
+	svg := report.BadgeSVG(BadgeOptions{Label: "<owners>"})
+	var document struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2000/svg svg"`
+	}
+	if err := xml.Unmarshal([]byte(svg), &document); err != nil {
+		t.Errorf("expected a valid SVG document, but got %s", err)
+	}
+	if !strings.Contains(svg, "&lt;owners&gt;: 50.0%") {
+		t.Error("expected the escaped label and the coverage ratio in the badge")
+	}
+	if !strings.Contains(svg, `fill="#fe7d37"`) {
+		t.Error("expected the badge to use the hex value of the named color")
+	}
+}
+
+func TestBadgeEndpoint(t *testing.T) {
+	report := Report{CoverageRatio: 0.9}
+	var endpoint strings.Builder
+	if err := NewBadgeEndpointFormatter(BadgeOptions{}).Format(&endpoint, &report); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"schemaVersion":1,"label":"codeowners","message":"90.0%","color":"brightgreen"}`
+	if endpoint.String() != expected+"\n" {
+		t.Errorf("expected %s, but got %s", expected, endpoint.String())
+	}
+	if _, err := report.ToFormat(ReportFormatBadgeEndpoint); err == nil {
+		t.Error("expected badges not to be registered without options")
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+	"github.com/urfave/cli/v2"
+)
+
+// badgeFlags configure the badge and badge-endpoint formats
+var badgeFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "badge-label",
+		Value: "codeowners",
+		Usage: "the label on the left of the badge",
+	},
+	&cli.StringSliceFlag{
+		Name:  "badge-color",
+		Usage: "color the badge when the coverage ratio is at least a value, given as `RATIO=COLOR` (may be repeated, default 0.9=brightgreen, 0.75=yellow, 0.5=orange, 0=red)",
+	},
+}
+
+// newBadgeOptions constructs a coverage.BadgeOptions from the badge flags of a cli.Context
+func newBadgeOptions(c *cli.Context) (coverage.BadgeOptions, error) {
+	options := coverage.BadgeOptions{Label: c.String("badge-label")}
+	for _, value := range c.StringSlice("badge-color") {
+		i := strings.Index(value, "=")
+		if i < 0 {
+			return options, fmt.Errorf("badge color %q must be given as RATIO=COLOR", value)
+		}
+		ratio, err := strconv.ParseFloat(value[:i], 64)
+		if err != nil {
+			return options, fmt.Errorf("badge color %q has an invalid ratio: %s", value, err)
+		}
+		options.Colors = append(options.Colors, coverage.BadgeColor{MinCoverageRatio: ratio, Color: value[i+1:]})
+	}
+	return options, nil
+}
@@ -2,6 +2,10 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
 
 	coverage "github.com/aaronsky/codeowners-coverage"
 	"github.com/urfave/cli/v2"
@@ -12,43 +16,227 @@ var app = cli.App{
 	Name:      "codeowners-coverage",
 	Usage:     "Return codeowners coverage report for a repository",
 	ArgsUsage: "[path to repository]",
-	Action:    executeCommand,
+	Flags: append(append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Value: "json",
+			Usage: "the format of the report (" + formatNames() + ")",
+		},
+		&cli.StringFlag{
+			Name:  "template",
+			Usage: "format the report with an inline Go text/template, instead of --format",
+		},
+		&cli.StringFlag{
+			Name:  "template-file",
+			Usage: "format the report with a Go text/template read from a file, instead of --format",
+		},
+		&cli.StringSliceFlag{
+			Name:  "output",
+			Usage: "write the report in a format to a path, or - for stdout, given as `FORMAT=PATH` (may be repeated, use the format template for --template or --template-file)",
+		},
+	}, reportFlags...), append(thresholdFlags, badgeFlags...)...),
+	Action: executeCommand,
+	Commands: []*cli.Command{
+		explainCommand,
+		whoOwnsCommand,
+		serveCommand,
+		compareCommand,
+	},
+}
+
+// reportFlags configure how the coverage report is produced, and are shared by every command that produces one
+var reportFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:  "max-depth",
+		Usage: "limit the depth of the directory tree in the report, or 0 for no limit",
+	},
+	&cli.BoolFlag{
+		Name:  "clean",
+		Usage: "run git-clean on the repository and crawl the disk instead of reading the index (deletes untracked and ignored files)",
+	},
+	&cli.StringFlag{
+		Name:  "ref",
+		Usage: "compute coverage for a branch, tag or SHA directly from git objects, without a checkout",
+	},
+	&cli.StringFlag{
+		Name:  "base",
+		Usage: "only cover files changed between this branch, tag or SHA and --ref (default HEAD), such as in a pull request",
+	},
+	&cli.StringFlag{
+		Name:  "platform",
+		Value: string(coverage.PlatformGitHub),
+		Usage: "the code hosting platform whose CODEOWNERS locations and precedence to follow (github, gitlab)",
+	},
+	&cli.StringFlag{
+		Name:  "codeowners",
+		Usage: "path to the CODEOWNERS file to use, relative to the root of the repository",
+	},
 }
 
 // arguments is a type that describes the simple arguments for this CLI
 type arguments struct {
-	Path string
+	Path       string
+	Outputs    []output
+	Template   coverage.Formatter
+	Options    coverage.Options
+	Thresholds coverage.Thresholds
+	Badge      coverage.BadgeOptions
+	// Baseline is nil unless --baseline is set, and is not loaded if it is being updated
+	Baseline       *coverage.Report
+	BaselinePath   string
+	UpdateBaseline bool
 }
 
-// newArguments constructs an Arguments object from a cli.Context Args object
-func newArguments(args cli.Args) (*arguments, error) {
-	path := args.First()
+// newArguments constructs an Arguments object from a cli.Context
+func newArguments(c *cli.Context) (*arguments, error) {
+	path := c.Args().First()
 	if path == "" {
 		return nil, fmt.Errorf("no path was supplied")
 	}
+	template, err := newTemplate(c)
+	if err != nil {
+		return nil, err
+	}
+	outputs, err := newOutputs(c, template != nil)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := newThresholds(c)
+	if err != nil {
+		return nil, err
+	}
+	badge, err := newBadgeOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	baseline, err := newBaseline(c)
+	if err != nil {
+		return nil, err
+	}
 	return &arguments{
-		Path: path,
+		Path:           path,
+		Outputs:        outputs,
+		Template:       template,
+		Options:        newOptions(c),
+		Thresholds:     thresholds,
+		Badge:          badge,
+		Baseline:       baseline,
+		BaselinePath:   c.String("baseline"),
+		UpdateBaseline: c.Bool("update-baseline"),
 	}, nil
 }
 
+// newOptions constructs a coverage.Options from the report flags of a cli.Context
+func newOptions(c *cli.Context) coverage.Options {
+	return coverage.Options{
+		MaxDirectoryDepth: c.Int("max-depth"),
+		CleanWorktree:     c.Bool("clean"),
+		Revision:          c.String("ref"),
+		BaseRevision:      c.String("base"),
+		Platform:          coverage.Platform(c.String("platform")),
+		CodeownersPath:    c.String("codeowners"),
+	}
+}
+
 // executeCommand is the action handler for `app` and is executed by the CLI
 func executeCommand(c *cli.Context) error {
-	args, err := newArguments(c.Args())
+	args, err := newArguments(c)
 	if err != nil {
 		return err
 	}
 
-	report, err := coverage.NewCoverageReport(args.Path)
+	// Tables of files are written as the report is produced, so that large repositories are not held in memory
+	table, err := openStreamedFilesTable(args)
 	if err != nil {
 		return err
 	}
+	options := args.Options
+	if table != nil {
+		options.FileHandler = table.WriteFile
+		options.OmitFiles = true
+	}
 
-	json, err := report.ToFormat(coverage.ReportFormatJSON)
+	report, err := coverage.NewCoverageReportWithOptions(args.Path, options)
+	if table != nil {
+		if closeErr := table.Close(); err == nil {
+			err = closeErr
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	fmt.Println(json)
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", warning)
+	}
+	for _, diagnostic := range report.Diagnostics {
+		fmt.Fprintln(os.Stderr, diagnostic)
+	}
 
-	return nil
+	if table == nil {
+		if err := writeOutputs(report, args); err != nil {
+			return err
+		}
+	}
+
+	return checkThresholds(report, args)
+}
+
+// newTemplate parses the template or template-file flag of a cli.Context, or returns nil if neither is set
+func newTemplate(c *cli.Context) (coverage.Formatter, error) {
+	text := c.String("template")
+	path := c.String("template-file")
+	if text == "" && path == "" {
+		return nil, nil
+	}
+	if text != "" && path != "" {
+		return nil, fmt.Errorf("--template and --template-file cannot be used together")
+	}
+	if c.IsSet("format") {
+		return nil, fmt.Errorf("--format cannot be used with --template or --template-file")
+	}
+	if path != "" {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --template-file: %s", err)
+		}
+		text = string(contents)
+	}
+	return coverage.NewTemplateFormatter(text)
+}
+
+// badgeFormats are formats that are not registered, since they are written with the options of the badge flags
+var badgeFormats = []coverage.ReportFormat{coverage.ReportFormatBadgeSVG, coverage.ReportFormatBadgeEndpoint}
+
+// parseFormat returns the registered or badge format with the given name, allowing "md" as an abbreviation
+// of "markdown"
+func parseFormat(name string) (coverage.ReportFormat, error) {
+	if name == "md" {
+		return coverage.ReportFormatMarkdown, nil
+	}
+	for _, format := range formats() {
+		if string(format) == name {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unknown format %q, expected one of: %s", name, formatNames())
+}
+
+// formats returns the registered formats and the badge formats, sorted alphabetically
+func formats() []coverage.ReportFormat {
+	formats := append(coverage.Formats(), badgeFormats...)
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i] < formats[j]
+	})
+	return formats
+}
+
+// formatNames lists the names of the formats, separated by commas
+func formatNames() string {
+	formats := formats()
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
 }
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+	"github.com/aaronsky/codeowners-coverage/internal/git"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/src-d/go-billy.v4/osfs"
+)
+
+// codeownersFlags are the flags shared by commands that look up owners without producing a coverage report
+var codeownersFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "repo",
+		Value: ".",
+		Usage: "path to the repository",
+	},
+	&cli.StringFlag{
+		Name:  "ref",
+		Usage: "read CODEOWNERS from a branch, tag or SHA rather than the worktree",
+	},
+	&cli.StringFlag{
+		Name:  "platform",
+		Value: string(codeowners.PlatformGitHub),
+		Usage: "the code hosting platform whose CODEOWNERS locations and precedence to follow (github, gitlab)",
+	},
+	&cli.StringFlag{
+		Name:  "codeowners",
+		Usage: "path to the CODEOWNERS file to use, relative to the root of the repository",
+	},
+}
+
+// loadCodeowners loads the CODEOWNERS file described by codeownersFlags
+func loadCodeowners(c *cli.Context) (*codeowners.File, error) {
+	options := codeowners.LoadOptions{
+		Platform: codeowners.Platform(c.String("platform")),
+		Path:     c.String("codeowners"),
+	}
+
+	if ref := c.String("ref"); ref != "" {
+		repository, err := git.Open(c.String("repo"))
+		if err != nil {
+			return nil, err
+		}
+		commit, err := git.ResolveCommit(repository, ref)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return nil, err
+		}
+		return codeowners.LoadFileFromTree(tree, options)
+	}
+
+	return codeowners.LoadFileFromFilesystem(osfs.New(c.String("repo")), options)
+}
+
+// normalizePath converts a path given on the command line to the slash-separated form matched by CODEOWNERS
+func normalizePath(path string) string {
+	path = filepath.ToSlash(filepath.Clean(path))
+	return strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+	"github.com/urfave/cli/v2"
+)
+
+// compareCommand prints how coverage changed between two saved JSON reports
+var compareCommand = &cli.Command{
+	Name:      "compare",
+	Usage:     "Print how coverage changed between two reports saved with --format json",
+	ArgsUsage: "[base report] [head report]",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print the comparison as JSON",
+		},
+	},
+	Action: executeCompareCommand,
+}
+
+// executeCompareCommand is the action handler for `compareCommand`
+func executeCompareCommand(c *cli.Context) error {
+	if c.NArg() != 2 {
+		return fmt.Errorf("expected a base and a head report, but got %d arguments", c.NArg())
+	}
+	base, err := loadReport(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	head, err := loadReport(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	comparison, err := coverage.Compare(base, head)
+	if err != nil {
+		return err
+	}
+	if c.Bool("json") {
+		bytes, err := json.Marshal(comparison)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(bytes))
+		return nil
+	}
+	printComparison(os.Stdout, comparison)
+	return nil
+}
+
+// loadReport reads a JSON report from a file
+func loadReport(path string) (*coverage.Report, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	report, err := coverage.LoadReport(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return report, nil
+}
+
+// printComparison writes a human-readable description of a comparison, omitting sections with no changes
+func printComparison(w io.Writer, comparison *coverage.Comparison) {
+	fmt.Fprintf(w, "coverage: %.1f%% -> %.1f%% (%+.1f points)\n",
+		comparison.BaseCoverageRatio*100, comparison.HeadCoverageRatio*100, comparison.CoverageRatioChange*100)
+
+	printPaths(w, "newly uncovered files", comparison.NewlyUncoveredFiles)
+	printPaths(w, "newly covered files", comparison.NewlyCoveredFiles)
+	printFiles(w, "added files", comparison.AddedFiles)
+	printFiles(w, "removed files", comparison.RemovedFiles)
+	if len(comparison.Owners) > 0 {
+		fmt.Fprintf(w, "owners with changed file counts (%d):\n", len(comparison.Owners))
+		for _, owner := range comparison.Owners {
+			fmt.Fprintf(w, "    %s: %d -> %d (%+d)\n", owner.Owner, owner.BaseFilesCount, owner.HeadFilesCount, owner.HeadFilesCount-owner.BaseFilesCount)
+		}
+	}
+	printRules(w, "rules added", comparison.AddedRules)
+	printRules(w, "rules removed", comparison.RemovedRules)
+}
+
+// printPaths writes a titled list of paths, unless it is empty
+func printPaths(w io.Writer, title string, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s (%d):\n", title, len(paths))
+	for _, path := range paths {
+		fmt.Fprintf(w, "    %s\n", path)
+	}
+}
+
+// printFiles writes a titled list of paths with their owners, unless it is empty
+func printFiles(w io.Writer, title string, files []coverage.FileCoverage) {
+	if len(files) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s (%d):\n", title, len(files))
+	for _, file := range files {
+		owners := strings.Join(file.Owners, " ")
+		if owners == "" {
+			owners = "(no owners)"
+		}
+		fmt.Fprintf(w, "    %s\t%s\n", file.Path, owners)
+	}
+}
+
+// printRules writes a titled list of rules with their line numbers, unless it is empty
+func printRules(w io.Writer, title string, rules []coverage.RuleChange) {
+	if len(rules) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s (%d):\n", title, len(rules))
+	for _, rule := range rules {
+		fmt.Fprintf(w, "    line %d: %s\t%s\n", rule.LineNumber, rule.Pattern, strings.Join(rule.Owners, " "))
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+	"github.com/urfave/cli/v2"
+)
+
+// explainCommand shows every CODEOWNERS entry that matches the given paths
+var explainCommand = &cli.Command{
+	Name:      "explain",
+	Usage:     "Show every CODEOWNERS rule that matches a path, and which one decides its owners",
+	ArgsUsage: "<path>...",
+	Flags:     codeownersFlags,
+	Action:    executeExplainCommand,
+}
+
+// executeExplainCommand is the action handler for `explainCommand`
+func executeExplainCommand(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return fmt.Errorf("no paths were supplied")
+	}
+
+	file, err := loadCodeowners(c)
+	if err != nil {
+		return err
+	}
+
+	for i, path := range c.Args().Slice() {
+		if i > 0 {
+			fmt.Fprintln(c.App.Writer)
+		}
+		printExplanation(c.App.Writer, file, file.Codeowners.Explain(normalizePath(path)))
+	}
+	return nil
+}
+
+// printExplanation writes the matching entries of an Explanation in file order, marking the winning entry
+func printExplanation(w io.Writer, file *codeowners.File, explanation codeowners.Explanation) {
+	fmt.Fprintf(w, "%s (%s)\n", explanation.Path, file.Path)
+	if len(explanation.Matches) == 0 {
+		fmt.Fprintln(w, "    no rule matches")
+	}
+	for i, entry := range explanation.Matches {
+		marker := "   "
+		if i == len(explanation.Matches)-1 {
+			marker = "-> "
+		}
+		fmt.Fprintf(w, " %s%s\n", marker, entry)
+	}
+	owners := "(none)"
+	if len(explanation.Owners) > 0 {
+		owners = strings.Join(explanation.Owners, " ")
+	}
+	fmt.Fprintf(w, "    owners: %s\n", owners)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+	"github.com/urfave/cli/v2"
+)
+
+// templateFormat is the name given to the template flags in --output, since it is not a registered format
+const templateFormat coverage.ReportFormat = "template"
+
+// stdoutPath is the path in --output that writes to stdout
+const stdoutPath = "-"
+
+// output is a destination for the report, in a particular format
+type output struct {
+	Format coverage.ReportFormat
+	Path   string
+}
+
+// newOutputs constructs the outputs of the report from the output flag of a cli.Context. If it is not set,
+// the report is written to stdout with --template if it is set, or otherwise in the --format.
+func newOutputs(c *cli.Context, hasTemplate bool) ([]output, error) {
+	values := c.StringSlice("output")
+	if len(values) == 0 {
+		if hasTemplate {
+			return []output{{Format: templateFormat, Path: stdoutPath}}, nil
+		}
+		format, err := parseFormat(c.String("format"))
+		if err != nil {
+			return nil, err
+		}
+		return []output{{Format: format, Path: stdoutPath}}, nil
+	}
+	if c.IsSet("format") {
+		return nil, fmt.Errorf("--format cannot be used with --output, which names the format of each output")
+	}
+
+	return parseOutputs(values, hasTemplate)
+}
+
+// parseOutputs parses outputs given as FORMAT=PATH. The template format is only allowed if hasTemplate is set,
+// in which case it must be used by one of the outputs, and at most one output may be written to stdout.
+func parseOutputs(values []string, hasTemplate bool) ([]output, error) {
+	outputs := make([]output, len(values))
+	usesTemplate := false
+	usesStdout := false
+	for i, value := range values {
+		separator := strings.Index(value, "=")
+		if separator < 0 {
+			return nil, fmt.Errorf("output %q must be given as FORMAT=PATH", value)
+		}
+		name, path := value[:separator], value[separator+1:]
+		if path == "" {
+			return nil, fmt.Errorf("output %q has no path, use %s for stdout", value, stdoutPath)
+		}
+		if path == stdoutPath {
+			if usesStdout {
+				return nil, fmt.Errorf("output %q writes to stdout, which is already used by another output", value)
+			}
+			usesStdout = true
+		}
+		if name == string(templateFormat) {
+			if !hasTemplate {
+				return nil, fmt.Errorf("output %q requires --template or --template-file", value)
+			}
+			usesTemplate = true
+			outputs[i] = output{Format: templateFormat, Path: path}
+			continue
+		}
+		format, err := parseFormat(name)
+		if err != nil {
+			return nil, err
+		}
+		outputs[i] = output{Format: format, Path: path}
+	}
+	if hasTemplate && !usesTemplate {
+		return nil, fmt.Errorf("the template is not used by any output, add --output %s=PATH", templateFormat)
+	}
+	return outputs, nil
+}
+
+// writeOutputs writes the report to each of the outputs of the arguments in turn
+func writeOutputs(report *coverage.Report, args *arguments) error {
+	for _, output := range args.Outputs {
+		if output.Path == stdoutPath {
+			if err := writeReport(os.Stdout, report, output.Format, args); err != nil {
+				return err
+			}
+			continue
+		}
+
+		file, err := os.Create(output.Path)
+		if err != nil {
+			return err
+		}
+		err = writeReport(file, report, output.Format, args)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s: %s", output.Path, err)
+		}
+	}
+	return nil
+}
+
+// streamedFilesTable is a table of files that is written as the report is produced, to a file or stdout
+type streamedFilesTable struct {
+	*coverage.FilesTable
+	path string
+	file *os.File
+}
+
+// openStreamedFilesTable opens the output of the arguments as a table of files that rows can be written to as
+// each file is resolved, if it is the only output, is a CSV or TSV table of files, and no baseline needs the files
+// of the report. Otherwise, it returns nil, and the report is written once it is complete.
+func openStreamedFilesTable(args *arguments) (*streamedFilesTable, error) {
+	if len(args.Outputs) != 1 || args.Baseline != nil || args.UpdateBaseline {
+		return nil, nil
+	}
+	output := args.Outputs[0]
+	if output.Format != coverage.ReportFormatCSV && output.Format != coverage.ReportFormatTSV {
+		return nil, nil
+	}
+
+	streamed := &streamedFilesTable{path: output.Path}
+	w := io.Writer(os.Stdout)
+	if output.Path != stdoutPath {
+		file, err := os.Create(output.Path)
+		if err != nil {
+			return nil, err
+		}
+		streamed.file = file
+		w = file
+	}
+	table, err := coverage.NewFilesTable(w, output.Format)
+	if err != nil {
+		return nil, err
+	}
+	streamed.FilesTable = table
+	return streamed, nil
+}
+
+// Close flushes the rows of the table, and closes its file unless it is stdout
+func (t *streamedFilesTable) Close() error {
+	err := t.Flush()
+	if t.file != nil {
+		if closeErr := t.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %s", t.path, err)
+	}
+	return nil
+}
+
+// writeReport writes the report to w in the given format. The template format uses the template of the
+// arguments, badges use the options of the badge flags, and every other format its registered coverage.Formatter.
+func writeReport(w io.Writer, report *coverage.Report, format coverage.ReportFormat, args *arguments) error {
+	switch format {
+	case templateFormat:
+		return args.Template.Format(w, report)
+	case coverage.ReportFormatBadgeSVG:
+		return coverage.NewBadgeFormatter(args.Badge).Format(w, report)
+	case coverage.ReportFormatBadgeEndpoint:
+		return coverage.NewBadgeEndpointFormatter(args.Badge).Format(w, report)
+	default:
+		return report.Write(w, format)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+)
+
+func TestParseOutputs(t *testing.T) {
+	cases := []struct {
+		values      []string
+		hasTemplate bool
+		outputs     []output
+	}{
+		{
+			[]string{"json=coverage.json", "md=-"},
+			false,
+			[]output{{Format: coverage.ReportFormatJSON, Path: "coverage.json"}, {Format: coverage.ReportFormatMarkdown, Path: stdoutPath}},
+		},
+		// paths are split at the first "="
+		{
+			[]string{"csv=a=b.csv"},
+			false,
+			[]output{{Format: coverage.ReportFormatCSV, Path: "a=b.csv"}},
+		},
+		{
+			[]string{"template=-", "json=coverage.json"},
+			true,
+			[]output{{Format: templateFormat, Path: stdoutPath}, {Format: coverage.ReportFormatJSON, Path: "coverage.json"}},
+		},
+	}
+
+	for _, c := range cases {
+		outputs, err := parseOutputs(c.values, c.hasTemplate)
+		if err != nil {
+			t.Errorf("expected %v to parse, but got %s", c.values, err)
+			continue
+		}
+		if !reflect.DeepEqual(outputs, c.outputs) {
+			t.Errorf("expected %v to parse as %v, but got %v", c.values, c.outputs, outputs)
+		}
+	}
+}
+
+func TestParseOutputsFails(t *testing.T) {
+	cases := []struct {
+		values      []string
+		hasTemplate bool
+	}{
+		// missing separator
+		{[]string{"json"}, false},
+		// missing path
+		{[]string{"json="}, false},
+		// unknown format
+		{[]string{"yaml=coverage.yaml"}, false},
+		// template without --template
+		{[]string{"template=report.txt"}, false},
+		// --template that no output uses
+		{[]string{"json=coverage.json"}, true},
+		// more than one output to stdout
+		{[]string{"json=-", "markdown=-"}, false},
+	}
+
+	for _, c := range cases {
+		if outputs, err := parseOutputs(c.values, c.hasTemplate); err == nil {
+			t.Errorf("expected %v to fail to parse, but got %v", c.values, outputs)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+	"github.com/urfave/cli/v2"
+)
+
+// serveCommand serves Prometheus metrics for a repository, refreshing them on an interval
+var serveCommand = &cli.Command{
+	Name:      "serve",
+	Usage:     "Serve Prometheus metrics for a repository on /metrics, refreshing them on an interval",
+	ArgsUsage: "[path to repository]",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "listen",
+			Value: ":9090",
+			Usage: "the address to serve metrics on",
+		},
+		&cli.DurationFlag{
+			Name:  "interval",
+			Value: 5 * time.Minute,
+			Usage: "how often to recompute the report",
+		},
+	}, reportFlags...),
+	Action: executeServeCommand,
+}
+
+// metricsHandler serves the metrics of the most recent report that was produced successfully
+type metricsHandler struct {
+	path    string
+	options coverage.Options
+
+	mu      sync.RWMutex
+	metrics string
+}
+
+// executeServeCommand is the action handler for `serveCommand`
+func executeServeCommand(c *cli.Context) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("no path was supplied")
+	}
+	interval := c.Duration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+	// --clean may also be given before the command name, where it is not seen by c.Bool
+	for _, ctx := range c.Lineage() {
+		if ctx.Bool("clean") {
+			return fmt.Errorf("--clean cannot be used with serve, as it would delete untracked files on every refresh")
+		}
+	}
+
+	handler := &metricsHandler{path: path, options: newOptions(c)}
+	// Fail on start rather than serving no metrics, since a later failure is more likely to be transient
+	if err := handler.refresh(); err != nil {
+		return err
+	}
+	go func() {
+		for range time.Tick(interval) {
+			if err := handler.refresh(); err != nil {
+				log.Printf("failed to refresh metrics, serving the previous report: %s", err)
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	return http.ListenAndServe(c.String("listen"), mux)
+}
+
+// refresh produces a new report and replaces the metrics being served, unless it fails
+func (h *metricsHandler) refresh() error {
+	report, err := coverage.NewCoverageReportWithOptions(h.path, h.options)
+	if err != nil {
+		return err
+	}
+	metrics, err := report.ToFormat(coverage.ReportFormatPrometheus)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.metrics = metrics
+	return nil
+}
+
+func (h *metricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	io.WriteString(w, h.metrics)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	coverage "github.com/aaronsky/codeowners-coverage"
+	"github.com/urfave/cli/v2"
+)
+
+// exitCodeThresholdViolated is the exit code when the report does not meet a threshold. Errors that
+// prevent a report from being generated exit with 1, via log.Fatal in main.
+const exitCodeThresholdViolated = 2
+
+// thresholdFlags configure the minimum levels of coverage the report is required to meet
+var thresholdFlags = []cli.Flag{
+	&cli.Float64Flag{
+		Name:  "fail-under",
+		Usage: "exit with code 2 if the coverage ratio is below this value, between 0 and 1",
+	},
+	&cli.IntFlag{
+		Name:  "max-uncovered",
+		Value: -1,
+		Usage: "exit with code 2 if more than this many files are uncovered, or -1 for no limit",
+	},
+	&cli.StringSliceFlag{
+		Name:  "directory-fail-under",
+		Usage: "exit with code 2 if the coverage ratio of a directory is below a value, given as `DIR=RATIO` (may be repeated)",
+	},
+	&cli.StringFlag{
+		Name:  "baseline",
+		Usage: "exit with code 2 if a file covered in this JSON report is now uncovered, or a new file is uncovered",
+	},
+	&cli.BoolFlag{
+		Name:  "update-baseline",
+		Usage: "replace the --baseline report with this report, rather than checking against it",
+	},
+}
+
+// newThresholds constructs a coverage.Thresholds from the threshold flags of a cli.Context
+func newThresholds(c *cli.Context) (coverage.Thresholds, error) {
+	thresholds := coverage.Thresholds{
+		MinCoverageRatio: c.Float64("fail-under"),
+	}
+	if !isRatio(thresholds.MinCoverageRatio) {
+		return thresholds, fmt.Errorf("--fail-under must be a ratio between 0 and 1, but was %g", thresholds.MinCoverageRatio)
+	}
+	if maxUncovered := c.Int("max-uncovered"); maxUncovered >= 0 {
+		thresholds.MaxUncoveredFiles = &maxUncovered
+	}
+	for _, value := range c.StringSlice("directory-fail-under") {
+		i := strings.LastIndex(value, "=")
+		if i < 0 {
+			return thresholds, fmt.Errorf("directory threshold %q must be given as DIR=RATIO", value)
+		}
+		ratio, err := strconv.ParseFloat(value[i+1:], 64)
+		if err != nil {
+			return thresholds, fmt.Errorf("directory threshold %q has an invalid ratio: %s", value, err)
+		}
+		if !isRatio(ratio) {
+			return thresholds, fmt.Errorf("directory threshold %q must have a ratio between 0 and 1", value)
+		}
+		if thresholds.MinDirectoryCoverageRatios == nil {
+			thresholds.MinDirectoryCoverageRatios = map[string]float64{}
+		}
+		thresholds.MinDirectoryCoverageRatios[normalizePath(value[:i])] = ratio
+	}
+	return thresholds, nil
+}
+
+// isRatio returns whether or not the value is between 0 and 1, inclusive
+func isRatio(value float64) bool {
+	return value >= 0 && value <= 1
+}
+
+// checkThresholds returns an error carrying exitCodeThresholdViolated and a summary of each violation of the
+// thresholds or the baseline, or nil if the report meets them all. If the baseline is being updated, the report
+// replaces it rather than being checked against it.
+func checkThresholds(report *coverage.Report, args *arguments) error {
+	var summary []string
+	for _, violation := range report.CheckThresholds(args.Thresholds) {
+		summary = append(summary, "threshold failed: "+violation)
+	}
+	if args.UpdateBaseline {
+		if err := writeBaseline(report, args.BaselinePath); err != nil {
+			return err
+		}
+	} else if args.Baseline != nil {
+		violations, err := report.CheckBaseline(args.Baseline)
+		if err != nil {
+			return err
+		}
+		for _, violation := range violations {
+			summary = append(summary, "baseline failed: "+violation)
+		}
+	}
+
+	if len(summary) == 0 {
+		return nil
+	}
+	return cli.Exit(strings.Join(summary, "\n"), exitCodeThresholdViolated)
+}
+
+// newBaseline loads the report named by the baseline flag of a cli.Context, or returns nil if it is not set
+// or is being updated. The baseline covers the whole repository, so it cannot be used with --base.
+func newBaseline(c *cli.Context) (*coverage.Report, error) {
+	path := c.String("baseline")
+	if path != "" && c.String("base") != "" {
+		return nil, fmt.Errorf("--baseline cannot be used with --base, since the baseline covers every file rather than those that changed")
+	}
+	if c.Bool("update-baseline") {
+		if path == "" {
+			return nil, fmt.Errorf("--update-baseline requires --baseline")
+		}
+		return nil, nil
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return loadReport(path)
+}
+
+// writeBaseline replaces the baseline with the report as JSON
+func writeBaseline(report *coverage.Report, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = report.Write(file, coverage.ReportFormatJSON)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update baseline %s: %s", path, err)
+	}
+	return nil
+}
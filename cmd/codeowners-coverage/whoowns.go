@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// whoOwnsCommand prints the owners of paths given as arguments or on stdin
+var whoOwnsCommand = &cli.Command{
+	Name:      "who-owns",
+	Usage:     "Print the owners of each path given as an argument, or read from stdin",
+	ArgsUsage: "[path...]",
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:    "null",
+			Aliases: []string{"0"},
+			Usage:   "paths read from stdin are separated by NUL characters rather than newlines",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print a JSON object per line rather than tab-separated values",
+		},
+		&cli.BoolFlag{
+			Name:  "owners-only",
+			Usage: "print only the owners of all paths, one per line, without duplicates",
+		},
+	}, codeownersFlags...),
+	Action: executeWhoOwnsCommand,
+}
+
+// pathOwners is the JSON form of a line of `whoOwnsCommand` output
+type pathOwners struct {
+	Path   string   `json:"path"`
+	Owners []string `json:"owners"`
+}
+
+// executeWhoOwnsCommand is the action handler for `whoOwnsCommand`
+func executeWhoOwnsCommand(c *cli.Context) error {
+	file, err := loadCodeowners(c)
+	if err != nil {
+		return err
+	}
+
+	paths := c.Args().Slice()
+	if len(paths) == 0 {
+		paths, err = readPaths(os.Stdin, c.Bool("null"))
+		if err != nil {
+			return err
+		}
+	}
+
+	w := bufio.NewWriter(c.App.Writer)
+	defer w.Flush()
+	encoder := json.NewEncoder(w)
+
+	if c.Bool("owners-only") {
+		var owners []string
+		seen := map[string]bool{}
+		for _, path := range paths {
+			for _, owner := range file.Codeowners.Owners(normalizePath(path)) {
+				if !seen[owner] {
+					seen[owner] = true
+					owners = append(owners, owner)
+				}
+			}
+		}
+		for _, owner := range owners {
+			if c.Bool("json") {
+				err = encoder.Encode(owner)
+			} else {
+				_, err = fmt.Fprintln(w, owner)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, path := range paths {
+		owners := file.Codeowners.Owners(normalizePath(path))
+		if c.Bool("json") {
+			err = encoder.Encode(pathOwners{Path: path, Owners: owners})
+		} else {
+			_, err = fmt.Fprintf(w, "%s\t%s\n", path, strings.Join(owners, " "))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPaths reads newline or NUL separated paths from r, skipping empty entries
+func readPaths(r io.Reader, nullDelimited bool) ([]string, error) {
+	s := bufio.NewScanner(r)
+	if nullDelimited {
+		s.Split(scanNullDelimited)
+	}
+
+	var paths []string
+	for s.Scan() {
+		path := s.Text()
+		if !nullDelimited {
+			path = strings.TrimRight(path, "\r")
+		}
+		if path == "" {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths, s.Err()
+}
+
+// scanNullDelimited is a bufio.SplitFunc that splits on NUL characters, as output by `git diff -z` and `find -print0`
+func scanNullDelimited(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
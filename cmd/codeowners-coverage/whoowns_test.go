@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadPaths(t *testing.T) {
+	cases := []struct {
+		input         string
+		nullDelimited bool
+		paths         []string
+	}{
+		{"a.go\nsrc/b.go\n", false, []string{"a.go", "src/b.go"}},
+		// the final path need not be terminated
+		{"a.go\nsrc/b.go", false, []string{"a.go", "src/b.go"}},
+		// empty lines are skipped
+		{"\na.go\n\n\nsrc/b.go\n\n", false, []string{"a.go", "src/b.go"}},
+		{"a.go\r\nsrc/b.go\r\n", false, []string{"a.go", "src/b.go"}},
+		{"", false, nil},
+		{"a.go\x00src/b.go\x00", true, []string{"a.go", "src/b.go"}},
+		{"a.go\x00src/b.go", true, []string{"a.go", "src/b.go"}},
+		{"\x00a.go\x00\x00src/b.go\x00\x00", true, []string{"a.go", "src/b.go"}},
+		// newlines, carriage returns and spaces are part of NUL-delimited paths
+		{"new\nline.go\x00carriage\r\x00with space.go\x00", true, []string{"new\nline.go", "carriage\r", "with space.go"}},
+		{"", true, nil},
+	}
+
+	for _, c := range cases {
+		paths, err := readPaths(strings.NewReader(c.input), c.nullDelimited)
+		if err != nil {
+			t.Errorf("expected %q to be read, but got %s", c.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(paths, c.paths) {
+			t.Errorf("expected %q to be read as %q, but got %q", c.input, c.paths, paths)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Comparison describes how coverage changed between two reports. Files in both reports are listed if they
+// became covered or uncovered, while files in only one report are listed as added or removed, whether or not
+// they are covered.
+type Comparison struct {
+	BaseCoverageRatio   float64 `json:"base_coverage_ratio"`
+	HeadCoverageRatio   float64 `json:"head_coverage_ratio"`
+	CoverageRatioChange float64 `json:"coverage_ratio_change"`
+	// NewlyUncoveredFiles are in both reports, and were covered in the base report but not in the head report
+	NewlyUncoveredFiles []string `json:"newly_uncovered_files"`
+	// NewlyCoveredFiles are in both reports, and were uncovered in the base report but covered in the head report
+	NewlyCoveredFiles []string `json:"newly_covered_files"`
+	// AddedFiles are only in the head report, with their owners in it
+	AddedFiles []FileCoverage `json:"added_files"`
+	// RemovedFiles are only in the base report, with their owners in it
+	RemovedFiles []FileCoverage `json:"removed_files"`
+	Owners       []OwnerChange  `json:"owners"`
+	AddedRules   []RuleChange   `json:"added_rules"`
+	RemovedRules []RuleChange   `json:"removed_rules"`
+}
+
+// OwnerChange describes an owner whose number of files changed between two reports
+type OwnerChange struct {
+	Owner          string `json:"owner"`
+	BaseFilesCount int    `json:"base_files_count"`
+	HeadFilesCount int    `json:"head_files_count"`
+}
+
+// RuleChange identifies a CODEOWNERS rule that was added or removed between two reports. Rules are compared by
+// their pattern and owners, since their line numbers change as other rules are added and removed.
+type RuleChange struct {
+	Pattern    string   `json:"pattern"`
+	LineNumber uint64   `json:"line_number"`
+	Owners     []string `json:"owners"`
+}
+
+// LoadReport reads a report that was previously written as JSON
+func LoadReport(r io.Reader) (*Report, error) {
+	var report Report
+	if err := json.NewDecoder(r).Decode(&report); err != nil {
+		return nil, fmt.Errorf("failed to load report: %s", err)
+	}
+	return &report, nil
+}
+
+// Compare describes how coverage changed from the base report to the head report. It fails if either report
+// counted files but does not list them, such as reports saved by older versions, since every file would
+// otherwise appear to be added or removed.
+func Compare(base, head *Report) (*Comparison, error) {
+	if !base.listsFiles() {
+		return nil, fmt.Errorf("the base report counts %d files, but does not list any of them", base.TotalFilesCount)
+	}
+	if !head.listsFiles() {
+		return nil, fmt.Errorf("the head report counts %d files, but does not list any of them", head.TotalFilesCount)
+	}
+
+	comparison := &Comparison{
+		BaseCoverageRatio:   base.CoverageRatio,
+		HeadCoverageRatio:   head.CoverageRatio,
+		CoverageRatioChange: head.CoverageRatio - base.CoverageRatio,
+		NewlyUncoveredFiles: []string{},
+		NewlyCoveredFiles:   []string{},
+		AddedFiles:          []FileCoverage{},
+		RemovedFiles:        []FileCoverage{},
+		Owners:              []OwnerChange{},
+		AddedRules:          []RuleChange{},
+		RemovedRules:        []RuleChange{},
+	}
+
+	baseFiles := map[string]FileCoverage{}
+	for _, file := range base.Files {
+		baseFiles[file.Path] = file
+	}
+	headFiles := map[string]bool{}
+	for _, file := range head.Files {
+		headFiles[file.Path] = true
+		baseFile, existed := baseFiles[file.Path]
+		switch {
+		case !existed:
+			comparison.AddedFiles = append(comparison.AddedFiles, file)
+		case len(file.Owners) == 0 && len(baseFile.Owners) > 0:
+			comparison.NewlyUncoveredFiles = append(comparison.NewlyUncoveredFiles, file.Path)
+		case len(file.Owners) > 0 && len(baseFile.Owners) == 0:
+			comparison.NewlyCoveredFiles = append(comparison.NewlyCoveredFiles, file.Path)
+		}
+	}
+	for _, file := range base.Files {
+		if !headFiles[file.Path] {
+			comparison.RemovedFiles = append(comparison.RemovedFiles, file)
+		}
+	}
+	sort.Strings(comparison.NewlyUncoveredFiles)
+	sort.Strings(comparison.NewlyCoveredFiles)
+	sortFiles(comparison.AddedFiles)
+	sortFiles(comparison.RemovedFiles)
+
+	owners := map[string]*OwnerChange{}
+	for _, owner := range base.Owners {
+		owners[owner.Owner] = &OwnerChange{Owner: owner.Owner, BaseFilesCount: owner.FilesCount}
+	}
+	for _, owner := range head.Owners {
+		if change, ok := owners[owner.Owner]; ok {
+			change.HeadFilesCount = owner.FilesCount
+		} else {
+			owners[owner.Owner] = &OwnerChange{Owner: owner.Owner, HeadFilesCount: owner.FilesCount}
+		}
+	}
+	for _, change := range owners {
+		if change.BaseFilesCount != change.HeadFilesCount {
+			comparison.Owners = append(comparison.Owners, *change)
+		}
+	}
+	sort.Slice(comparison.Owners, func(i, j int) bool {
+		return comparison.Owners[i].Owner < comparison.Owners[j].Owner
+	})
+
+	comparison.AddedRules = rulesDifference(head.Rules, base.Rules)
+	comparison.RemovedRules = rulesDifference(base.Rules, head.Rules)
+
+	return comparison, nil
+}
+
+// listsFiles returns whether or not the report lists every file it counted, which reports saved before files were
+// included in them, or produced with Options.OmitFiles, do not
+func (r *Report) listsFiles() bool {
+	return r.TotalFilesCount == 0 || len(r.Files) > 0
+}
+
+// Changed returns whether or not anything differs between the compared reports
+func (c *Comparison) Changed() bool {
+	return c.CoverageRatioChange != 0 ||
+		len(c.NewlyUncoveredFiles) > 0 ||
+		len(c.NewlyCoveredFiles) > 0 ||
+		len(c.AddedFiles) > 0 ||
+		len(c.RemovedFiles) > 0 ||
+		len(c.Owners) > 0 ||
+		len(c.AddedRules) > 0 ||
+		len(c.RemovedRules) > 0
+}
+
+// sortFiles sorts files by their path
+func sortFiles(files []FileCoverage) {
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+}
+
+// rulesDifference returns the rules of a that have no counterpart in b, with the same pattern and owners.
+// Rules that appear more than once are matched up one for one.
+func rulesDifference(a, b []RuleCoverage) []RuleChange {
+	remaining := map[string]int{}
+	for _, rule := range b {
+		remaining[ruleKey(rule)]++
+	}
+	difference := []RuleChange{}
+	for _, rule := range a {
+		key := ruleKey(rule)
+		if remaining[key] > 0 {
+			remaining[key]--
+			continue
+		}
+		difference = append(difference, RuleChange{Pattern: rule.Pattern, LineNumber: rule.LineNumber, Owners: rule.Owners})
+	}
+	return difference
+}
+
+// ruleKey identifies a rule by its pattern and owners
+func ruleKey(rule RuleCoverage) string {
+	return rule.Pattern + "\x00" + strings.Join(rule.Owners, "\x00")
+}
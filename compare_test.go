@@ -0,0 +1,129 @@
+package coverage
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadReport(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "src/main.go", Owners: []string{"@org/go"}, Rule: &RuleMatch{Pattern: "*.go", LineNumber: 1}},
+		{Path: "notes.txt", Owners: []string{}},
+	}
+	report := Report{
+		RemoteURL:         "git@github.com:org/repo.git",
+		SHA:               "abc123",
+		CoveredFilesCount: 1,
+		TotalFilesCount:   2,
+		CoverageRatio:     0.5,
+		Files:             files,
+		UncoveredFiles:    []string{"notes.txt"},
+		Owners:            []OwnerCoverage{{Owner: "@org/go", FilesCount: 1, FilesRatio: 0.5, RulesCount: 1, TopDirectories: []OwnedDirectoryCount{{Path: "src", FilesCount: 1}}}},
+		Directories:       newDirectoryCoverage(files, 0),
+		Rules:             []RuleCoverage{{Pattern: "*.go", LineNumber: 1, Owners: []string{"@org/go"}, MatchedFilesCount: 1, DecidedFilesCount: 1}},
+	}
+	json, err := report.ToFormat(ReportFormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReport(strings.NewReader(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*loaded, report) {
+		t.Errorf("expected the loaded report to equal the original\n%+v\nbut it was\n%+v", report, *loaded)
+	}
+}
+
+func TestLoadReportInvalid(t *testing.T) {
+	if _, err := LoadReport(strings.NewReader("not json")); err == nil {
+		t.Error("expected loading invalid JSON to fail")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := &Report{
+		CoverageRatio: 0.5,
+		Files: []FileCoverage{
+			{Path: "a.go", Owners: []string{"@org/go"}},
+			{Path: "b.go", Owners: []string{"@org/go"}},
+			{Path: "c.txt", Owners: []string{}},
+			{Path: "d.txt", Owners: []string{}},
+			{Path: "f.go", Owners: []string{"@org/go"}},
+		},
+		Owners: []OwnerCoverage{{Owner: "@org/go", FilesCount: 2}, {Owner: "@org/docs", FilesCount: 1}},
+		Rules: []RuleCoverage{
+			{Pattern: "*.go", LineNumber: 1, Owners: []string{"@org/go"}},
+			{Pattern: "*.md", LineNumber: 2, Owners: []string{"@org/docs"}},
+		},
+	}
+	head := &Report{
+		CoverageRatio: 0.6,
+		Files: []FileCoverage{
+			{Path: "a.go", Owners: []string{"@org/go"}},
+			{Path: "b.go", Owners: []string{}},
+			{Path: "c.txt", Owners: []string{"@org/docs"}},
+			{Path: "d.txt", Owners: []string{}},
+			{Path: "e.txt", Owners: []string{}},
+		},
+		Owners: []OwnerCoverage{{Owner: "@org/go", FilesCount: 1}, {Owner: "@org/docs", FilesCount: 1}},
+		Rules: []RuleCoverage{
+			{Pattern: "*.txt", LineNumber: 1, Owners: []string{"@org/docs"}},
+			{Pattern: "*.go", LineNumber: 2, Owners: []string{"@org/go"}},
+		},
+	}
+
+	comparison, err := Compare(base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comparison.CoverageRatioChange < 0.099 || comparison.CoverageRatioChange > 0.101 {
+		t.Errorf("expected a change in coverage ratio of 0.1, but it was %f", comparison.CoverageRatioChange)
+	}
+	if !reflect.DeepEqual(comparison.NewlyUncoveredFiles, []string{"b.go"}) {
+		t.Errorf("expected b.go to be newly uncovered, but got %v", comparison.NewlyUncoveredFiles)
+	}
+	if !reflect.DeepEqual(comparison.NewlyCoveredFiles, []string{"c.txt"}) {
+		t.Errorf("expected c.txt to be newly covered, but got %v", comparison.NewlyCoveredFiles)
+	}
+	if !reflect.DeepEqual(comparison.AddedFiles, []FileCoverage{{Path: "e.txt", Owners: []string{}}}) {
+		t.Errorf("expected e.txt to be added, but got %v", comparison.AddedFiles)
+	}
+	if !reflect.DeepEqual(comparison.RemovedFiles, []FileCoverage{{Path: "f.go", Owners: []string{"@org/go"}}}) {
+		t.Errorf("expected f.go to be removed, but got %v", comparison.RemovedFiles)
+	}
+	if !reflect.DeepEqual(comparison.Owners, []OwnerChange{{Owner: "@org/go", BaseFilesCount: 2, HeadFilesCount: 1}}) {
+		t.Errorf("expected only @org/go to change, but got %v", comparison.Owners)
+	}
+	if len(comparison.AddedRules) != 1 || comparison.AddedRules[0].Pattern != "*.txt" {
+		t.Errorf("expected *.txt to be added, but got %v", comparison.AddedRules)
+	}
+	if len(comparison.RemovedRules) != 1 || comparison.RemovedRules[0].Pattern != "*.md" {
+		t.Errorf("expected *.md to be removed, but got %v", comparison.RemovedRules)
+	}
+	if !comparison.Changed() {
+		t.Error("expected the comparison to have changes")
+	}
+	if unchanged, err := Compare(base, base); err != nil || unchanged.Changed() {
+		t.Errorf("expected a report compared with itself to have no changes, but got %+v (%v)", unchanged, err)
+	}
+}
+
+func TestCompareWithoutFiles(t *testing.T) {
+	withoutFiles := &Report{TotalFilesCount: 2, CoveredFilesCount: 1, CoverageRatio: 0.5}
+	withFiles := &Report{
+		TotalFilesCount: 1,
+		Files:           []FileCoverage{{Path: "a.go", Owners: []string{}}},
+	}
+	if _, err := Compare(withoutFiles, withFiles); err == nil {
+		t.Error("expected a base report that does not list its files to be rejected")
+	}
+	if _, err := Compare(withFiles, withoutFiles); err == nil {
+		t.Error("expected a head report that does not list its files to be rejected")
+	}
+	if _, err := Compare(&Report{}, withFiles); err != nil {
+		t.Errorf("expected an empty base report to be accepted, but got %s", err)
+	}
+}
@@ -2,77 +2,275 @@
 package coverage
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
 	"github.com/aaronsky/codeowners-coverage/internal/git"
 	"gopkg.in/src-d/go-billy.v4"
+	go_git "gopkg.in/src-d/go-git.v4"
 )
 
 // Report contains information on the codeowner coverage of files in a repository
 type Report struct {
-	RemoteURL         string  `json:"remote_url"`
-	SHA               string  `json:"sha"`
-	CoveredFilesCount int     `json:"covered_files_count"`
-	TotalFilesCount   int     `json:"total_files_count"`
-	CoverageRatio     float64 `json:"coverage_ratio"`
+	RemoteURL         string             `json:"remote_url"`
+	SHA               string             `json:"sha"`
+	CoveredFilesCount int                `json:"covered_files_count"`
+	TotalFilesCount   int                `json:"total_files_count"`
+	CoverageRatio     float64            `json:"coverage_ratio"`
+	CodeownersPath    string             `json:"codeowners_path,omitempty"`
+	Warnings          []string           `json:"warnings,omitempty"`
+	Diagnostics       []Diagnostic       `json:"diagnostics,omitempty"`
+	Files             []FileCoverage     `json:"files,omitempty"`
+	UncoveredFiles    []string           `json:"uncovered_files,omitempty"`
+	Owners            []OwnerCoverage    `json:"owners,omitempty"`
+	Directories       *DirectoryCoverage `json:"directories,omitempty"`
+	Rules             []RuleCoverage     `json:"rules,omitempty"`
+	Diff              *DiffCoverage      `json:"diff,omitempty"`
 }
 
-// NewCoverageReport produces a coverage report from the given repository
-// Modifies state of the given repository by performing a git-clean.
+// Options configures how a Report is produced
+type Options struct {
+	// MaxDirectoryDepth limits the depth of the directory tree in the Report. 0 means no limit.
+	MaxDirectoryDepth int
+	// CleanWorktree performs a git-clean on the repository and crawls the disk for files, rather than
+	// reading tracked files from the index. This permanently deletes untracked and ignored files.
+	CleanWorktree bool
+	// Revision is a branch, tag or SHA to produce the Report for. When set, files and the CODEOWNERS file
+	// are read from the commit's tree rather than the worktree, which also allows bare repositories.
+	Revision string
+	// BaseRevision is a branch, tag or SHA to compare Revision, or HEAD if unset, against. When set, the Report
+	// only covers the files that changed between the two, such as those in a pull request.
+	BaseRevision string
+	// Platform determines where the CODEOWNERS file is looked for, and which takes precedence. Defaults to PlatformGitHub.
+	Platform Platform
+	// CodeownersPath is the slash-separated path of the CODEOWNERS file to use, relative to the root of the
+	// repository. When set, the CODEOWNERS file is not searched for.
+	CodeownersPath string
+	// FileHandler, when set, is called with the ownership of each file as soon as it is resolved, in the order
+	// of Report.Files. An error returned by it stops the Report from being produced.
+	FileHandler func(FileCoverage) error
+	// OmitFiles leaves Files and UncoveredFiles out of the Report, so that the files of a large repository are
+	// not held in memory when they are handled by FileHandler instead. Counts, owners, directories and rules
+	// are still computed.
+	OmitFiles bool
+}
+
+// Platform identifies a code hosting service, which determines where CODEOWNERS files are looked for
+type Platform = codeowners.Platform
+
+const (
+	// PlatformGitHub uses the first CODEOWNERS file found in the .github/, root, and docs/ directories
+	PlatformGitHub = codeowners.PlatformGitHub
+	// PlatformGitLab uses the first CODEOWNERS file found in the root, docs/, and .gitlab/ directories
+	PlatformGitLab = codeowners.PlatformGitLab
+)
+
+// FileCoverage contains the resolved ownership of a single file in a repository
+type FileCoverage struct {
+	Path   string     `json:"path"`
+	Owners []string   `json:"owners"`
+	Rule   *RuleMatch `json:"rule,omitempty"`
+}
+
+// Diagnostic describes a problem found while parsing the CODEOWNERS file
+type Diagnostic struct {
+	Path     string `json:"path"`
+	Line     uint64 `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Message  string `json:"message"`
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s: %s", d.Path, d.Line, d.Column, d.Severity, d.Message)
+}
+
+// RuleMatch identifies the CODEOWNERS entry that decided the ownership of a file
+type RuleMatch struct {
+	Pattern    string `json:"pattern"`
+	LineNumber uint64 `json:"line_number"`
+}
+
+// NewCoverageReport produces a coverage report from the given repository using the default Options.
+// The repository is not modified.
 func NewCoverageReport(path string) (*Report, error) {
+	return NewCoverageReportWithOptions(path, Options{})
+}
+
+// NewCoverageReportWithOptions produces a coverage report from the given repository.
+// The repository is only modified if Options.CleanWorktree is set.
+func NewCoverageReportWithOptions(path string, options Options) (*Report, error) {
 	repository, err := git.Open(path)
 	if err != nil {
 		return nil, err
 	}
 
-	remote, err := repository.Remote("origin")
+	remoteURL, err := originURL(repository)
 	if err != nil {
 		return nil, err
 	}
-	remoteURL := remote.Config().URLs[0]
-	headSHA, err := repository.Head()
+
+	report := &Report{RemoteURL: remoteURL}
+	if options.BaseRevision != "" {
+		err = report.setCoverageForDiff(repository, options)
+	} else if options.Revision != "" {
+		err = report.setCoverageForRevision(repository, options)
+	} else {
+		err = report.setCoverageForWorktree(repository, options)
+	}
 	if err != nil {
 		return nil, err
 	}
+	return report, nil
+}
+
+// originURL returns the first URL of the origin remote, or an empty string if the repository has no origin
+func originURL(repository *go_git.Repository) (string, error) {
+	remote, err := repository.Remote("origin")
+	if err == go_git.ErrRemoteNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", nil
+}
+
+// setCoverageForWorktree mutates the Report object to store the coverage of the files checked out at HEAD
+func (r *Report) setCoverageForWorktree(repository *go_git.Repository, options Options) error {
+	headSHA, err := repository.Head()
+	if err != nil {
+		return err
+	}
+	r.SHA = headSHA.Hash().String()
 
 	worktree, err := repository.Worktree()
 	if err != nil {
-		return nil, err
+		return err
 	}
 	status, err := worktree.Status()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	git.CleanWorktree(worktree)
 
 	fs := worktree.Filesystem
-	owners, err := codeowners.LoadFromFilesystem(fs)
+	file, err := codeowners.LoadFileFromFilesystem(fs, options.loadOptions())
 	if err != nil {
-		return nil, err
+		return err
+	}
+	r.setCodeownersFile(file)
+	owners := file.Codeowners
+
+	if options.CleanWorktree {
+		err = git.CleanWorktree(worktree)
+		if err != nil {
+			return err
+		}
+		return r.setCoverage(status, fs, owners, options)
+	}
+
+	paths, err := trackedFiles(repository, status, file.Path, options.Platform)
+	if err != nil {
+		return err
+	}
+	return r.setCoverageForPaths(paths, owners, options)
+}
+
+// setCoverageForRevision mutates the Report object to store the coverage of the files in the commit
+// identified by Options.Revision, reading directly from git objects rather than the worktree
+func (r *Report) setCoverageForRevision(repository *go_git.Repository, options Options) error {
+	commit, err := git.ResolveCommit(repository, options.Revision)
+	if err != nil {
+		return err
+	}
+	r.SHA = commit.Hash.String()
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	file, err := codeowners.LoadFileFromTree(tree, options.loadOptions())
+	if err != nil {
+		return err
+	}
+	r.setCodeownersFile(file)
+
+	paths, err := git.TreeFiles(tree)
+	if err != nil {
+		return err
+	}
+	return r.setCoverageForPaths(withoutCodeowners(paths, file.Path, options.Platform), file.Codeowners, options)
+}
+
+// loadOptions returns the options for finding the CODEOWNERS file
+func (o Options) loadOptions() codeowners.LoadOptions {
+	return codeowners.LoadOptions{Platform: o.Platform, Path: o.CodeownersPath}
+}
+
+// setCodeownersFile mutates the Report object to store where the CODEOWNERS file was loaded from and
+// the problems found parsing it, and to warn about any other CODEOWNERS files that were ignored
+func (r *Report) setCodeownersFile(file *codeowners.File) {
+	r.CodeownersPath = file.Path
+	if len(file.Ignored) > 0 {
+		r.Warnings = append(r.Warnings, fmt.Sprintf("multiple CODEOWNERS files found: using %s and ignoring %s", file.Path, strings.Join(file.Ignored, ", ")))
+	}
+	for _, diagnostic := range file.Diagnostics {
+		r.Diagnostics = append(r.Diagnostics, Diagnostic{
+			Path:     file.Path,
+			Line:     diagnostic.Line,
+			Column:   diagnostic.Column,
+			Severity: string(diagnostic.Severity),
+			Code:     string(diagnostic.Code),
+			Message:  diagnostic.Message,
+		})
 	}
+}
 
-	report := &Report{RemoteURL: remoteURL, SHA: headSHA.Hash().String()}
-	err = report.setCoverage(status, fs, owners)
+// trackedFiles lists the files tracked in the index of the repository, excluding CODEOWNERS files
+func trackedFiles(repository *go_git.Repository, status git.Status, codeownersPath string, platform Platform) ([]string, error) {
+	paths, err := git.TrackedFiles(repository, status)
 	if err != nil {
 		return nil, err
 	}
-	return report, nil
+	return withoutCodeowners(paths, codeownersPath, platform), nil
+}
+
+// withoutCodeowners filters the platform's CODEOWNERS files, including the one at codeownersPath, out of the given
+// slash-separated paths
+func withoutCodeowners(paths []string, codeownersPath string, platform Platform) []string {
+	var filtered []string
+	for _, path := range paths {
+		if isCodeowners(path, codeownersPath, platform) {
+			// skip codeowners
+			continue
+		}
+		filtered = append(filtered, path)
+	}
+	return filtered
 }
 
-// setCoverage mutates the Report object to store information on covered files and the ratio of coverage
-func (r *Report) setCoverage(status git.Status, fs billy.Filesystem, owners codeowners.Codeowners) error {
-	var totalFilesCount int
+// isCodeowners returns whether the slash-separated path is one of the platform's CODEOWNERS locations,
+// or the CODEOWNERS file that was loaded
+func isCodeowners(path string, codeownersPath string, platform Platform) bool {
+	return codeowners.SlashPathIsCodeowners(path, platform) || path == codeownersPath
+}
+
+// setCoverage mutates the Report object to store information on covered files and the ratio of coverage,
+// crawling the given filesystem for files that are not untracked
+func (r *Report) setCoverage(status git.Status, fs billy.Filesystem, owners codeowners.Codeowners, options Options) error {
 	var filesToCheckCoverage []string
-	var coveredFilesCount int
 
 	err := git.WalkTree(fs, func(path string, info os.FileInfo, err error) error {
 		if !info.Mode().IsRegular() {
 			// not file
 			return nil
-		} else if codeowners.PathIsCodeowners(path, fs) {
+		} else if codeowners.PathIsCodeowners(path, fs, options.Platform) || filepath.ToSlash(path) == options.CodeownersPath {
 			// skip codeowners
 			return nil
 		} else if status.IsUntracked(path) {
@@ -80,8 +278,7 @@ func (r *Report) setCoverage(status git.Status, fs billy.Filesystem, owners code
 			return nil
 		}
 
-		totalFilesCount++
-		filesToCheckCoverage = append(filesToCheckCoverage, path)
+		filesToCheckCoverage = append(filesToCheckCoverage, filepath.ToSlash(path))
 
 		return nil
 	})
@@ -89,40 +286,52 @@ func (r *Report) setCoverage(status git.Status, fs billy.Filesystem, owners code
 		return err
 	}
 
-	for _, path := range filesToCheckCoverage {
-		ownersForPath := owners.Owners(path)
-		if len(ownersForPath) > 0 {
+	return r.setCoverageForPaths(filesToCheckCoverage, owners, options)
+}
+
+// setCoverageForPaths mutates the Report object to store information on the coverage of the given
+// slash-separated paths, passing each file to Options.FileHandler as it is resolved
+func (r *Report) setCoverageForPaths(paths []string, owners codeowners.Codeowners, options Options) error {
+	var coveredFilesCount int
+	var files []FileCoverage
+	var uncoveredFiles []string
+
+	analysis := codeowners.NewAnalysis(owners)
+	ownersTally := newOwnersTally(owners)
+	directories := newDirectoryTree(options.MaxDirectoryDepth)
+	for _, path := range paths {
+		file := FileCoverage{Path: path, Owners: []string{}}
+		if entry := analysis.Add(path); entry != nil {
+			file.Owners = entry.Owners
+			file.Rule = &RuleMatch{Pattern: entry.Pattern.Source(), LineNumber: entry.LineNumber()}
+		}
+		ownersTally.add(file)
+		directories.add(file)
+		if options.FileHandler != nil {
+			if err := options.FileHandler(file); err != nil {
+				return err
+			}
+		}
+
+		if len(file.Owners) > 0 {
 			coveredFilesCount++
+		} else if !options.OmitFiles {
+			uncoveredFiles = append(uncoveredFiles, file.Path)
+		}
+		if !options.OmitFiles {
+			files = append(files, file)
 		}
 	}
 
 	r.CoveredFilesCount = coveredFilesCount
-	r.TotalFilesCount = totalFilesCount
-	if totalFilesCount > 0 {
-		r.CoverageRatio = float64(coveredFilesCount) / float64(totalFilesCount)
+	r.TotalFilesCount = len(paths)
+	r.Files = files
+	r.UncoveredFiles = uncoveredFiles
+	r.Owners = ownersTally.breakdown()
+	r.Directories = directories.finish()
+	r.Rules = newRulesCoverage(analysis)
+	if r.TotalFilesCount > 0 {
+		r.CoverageRatio = float64(coveredFilesCount) / float64(r.TotalFilesCount)
 	}
-
 	return nil
 }
-
-type reportFormat string
-
-const (
-	// ReportFormatJSON is a constant representing the JSON format for a Report object
-	ReportFormatJSON reportFormat = "json"
-)
-
-// ToFormat converts the report to a string in the given format.
-// Currently only "json" is supported.
-func (r *Report) ToFormat(format reportFormat) (string, error) {
-	switch format {
-	case ReportFormatJSON:
-		bytes, err := json.Marshal(r)
-		if err != nil {
-			return "", err
-		}
-		return string(bytes), nil
-	default:
-		return "", fmt.Errorf("unsupported reportFormat")
-	}
-}
@@ -2,13 +2,19 @@ package coverage
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
 	"github.com/aaronsky/codeowners-coverage/internal/git"
 	"gopkg.in/src-d/go-billy.v4"
 	"gopkg.in/src-d/go-billy.v4/memfs"
 	go_git "gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
 )
 
 func TestSetCoverage(t *testing.T) {
@@ -23,7 +29,7 @@ func TestSetCoverage(t *testing.T) {
 		t.Error(err)
 	}
 
-	err = report.setCoverage(mockStatus, mockFs, owners)
+	err = report.setCoverage(mockStatus, mockFs, owners, Options{})
 	if err != nil {
 		t.Error(err)
 	}
@@ -38,6 +44,246 @@ func TestSetCoverage(t *testing.T) {
 	}
 }
 
+func TestSetCoverageFiles(t *testing.T) {
+	report := Report{}
+
+	mockStatus, mockFs, _ := setupPopulatedFilesystem()
+	owners, err := codeowners.LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = report.setCoverage(mockStatus, mockFs, owners, Options{})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(report.Files) != 5 {
+		t.Fatalf("expected 5 file entries, but there were %d", len(report.Files))
+	}
+	if len(report.UncoveredFiles) != 3 {
+		t.Errorf("expected 3 uncovered files, but there were %d", len(report.UncoveredFiles))
+	}
+	for _, file := range report.Files {
+		switch file.Path {
+		case "index.js", "src/app.js":
+			if len(file.Owners) != 1 || file.Owners[0] != "@org/team_reviewers" {
+				t.Errorf("expected %s to be owned by @org/team_reviewers, but it was owned by %v", file.Path, file.Owners)
+			}
+			if file.Rule == nil {
+				t.Errorf("expected %s to have a matching rule", file.Path)
+			} else if file.Rule.Pattern != "*.js" || file.Rule.LineNumber != 1 {
+				t.Errorf("expected %s to be matched by line 1 (*.js), but it was matched by %+v", file.Path, *file.Rule)
+			}
+		default:
+			if len(file.Owners) != 0 {
+				t.Errorf("expected %s to have no owners, but it was owned by %v", file.Path, file.Owners)
+			}
+			if file.Rule != nil {
+				t.Errorf("expected %s to have no matching rule", file.Path)
+			}
+		}
+	}
+}
+
+func TestTrackedFilesSkipsCodeowners(t *testing.T) {
+	fs := memfs.New()
+	repository, err := go_git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{".github/CODEOWNERS", "index.js", "untracked.js"} {
+		file, _ := fs.Create(path)
+		file.Write([]byte(path))
+		file.Close()
+	}
+	for _, path := range []string{".github/CODEOWNERS", "index.js"} {
+		if _, err := worktree.Add(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := trackedFiles(repository, status, ".github/CODEOWNERS", PlatformGitHub)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(paths) != 1 || paths[0] != "index.js" {
+		t.Errorf("expected tracked files to be [index.js], but they were %v", paths)
+	}
+}
+
+func TestSetCoverageCountsOtherPlatformsCodeowners(t *testing.T) {
+	repository, fs := setupRepository(t)
+	head := commitFiles(t, repository, fs, map[string]string{
+		".github/CODEOWNERS": "* @org/github",
+		".gitlab/CODEOWNERS": "* @org/gitlab",
+		"index.js":           "",
+	})
+
+	for platform, expected := range map[Platform][]string{
+		PlatformGitHub: {".gitlab/CODEOWNERS", "index.js"},
+		PlatformGitLab: {".github/CODEOWNERS", "index.js"},
+	} {
+		report := Report{}
+		if err := report.setCoverageForRevision(repository, Options{Revision: head.String(), Platform: platform}); err != nil {
+			t.Fatal(err)
+		}
+		var paths []string
+		for _, file := range report.Files {
+			paths = append(paths, file.Path)
+		}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("expected %s to count %v, but it counted %v", platform, expected, paths)
+		}
+	}
+}
+
+func TestSetCoverageForRevision(t *testing.T) {
+	repository, fs := setupRepository(t)
+	first := commitFiles(t, repository, fs, map[string]string{
+		".github/CODEOWNERS": "*.js @org/team_reviewers",
+		"index.js":           "",
+		"README.md":          "",
+	})
+	commitFiles(t, repository, fs, map[string]string{
+		".github/CODEOWNERS": "* @org/everyone",
+		"src/app.js":         "",
+	})
+
+	report := Report{}
+	err := report.setCoverageForRevision(repository, Options{Revision: first.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SHA != first.String() {
+		t.Errorf("expected sha to be %s, but it was %s", first, report.SHA)
+	}
+	if report.TotalFilesCount != 2 {
+		t.Errorf("expected total file count to be 2, but it was %d", report.TotalFilesCount)
+	}
+	if report.CoveredFilesCount != 1 {
+		t.Errorf("expected covered file count to be 1, but it was %d", report.CoveredFilesCount)
+	}
+
+	err = report.setCoverageForRevision(repository, Options{Revision: "master"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalFilesCount != 3 || report.CoveredFilesCount != 3 {
+		t.Errorf("expected 3 of 3 files to be covered at master, but %d of %d were", report.CoveredFilesCount, report.TotalFilesCount)
+	}
+}
+
+func TestSetCoverageStreamsFiles(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitFiles(t, repository, fs, map[string]string{
+		".github/CODEOWNERS": "*.js @org/frontend",
+		"index.js":           "",
+		"src/app.js":         "",
+		"README.md":          "",
+	})
+
+	var csv strings.Builder
+	table, err := NewFilesTable(&csv, ReportFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Report{}
+	err = report.setCoverageForRevision(repository, Options{Revision: "HEAD", FileHandler: table.WriteFile, OmitFiles: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "path,owners,pattern,line,covered\n" +
+		"README.md,,,,false\n" +
+		"index.js,@org/frontend,*.js,1,true\n" +
+		"src/app.js,@org/frontend,*.js,1,true\n"
+	if csv.String() != expected {
+		t.Errorf("expected the files to be written as\n%s\nbut got\n%s", expected, csv.String())
+	}
+	if report.Files != nil || report.UncoveredFiles != nil {
+		t.Errorf("expected the files to be omitted from the report, but got %v and %v", report.Files, report.UncoveredFiles)
+	}
+	if report.TotalFilesCount != 3 || report.CoveredFilesCount != 2 || report.Directories.TotalFilesCount != 3 || len(report.Owners) != 1 {
+		t.Errorf("expected the report to still count every file, but got %+v", report)
+	}
+}
+
+func TestSetCoverageWarnsOfIgnoredCodeowners(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitFiles(t, repository, fs, map[string]string{
+		".github/CODEOWNERS": "*.js @org/team_reviewers",
+		"CODEOWNERS":         "* @org/everyone",
+		"index.js":           "",
+		"README.md":          "",
+	})
+
+	report := Report{}
+	err := report.setCoverageForRevision(repository, Options{Revision: "HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.CodeownersPath != ".github/CODEOWNERS" {
+		t.Errorf("expected .github/CODEOWNERS to be used, but %s was", report.CodeownersPath)
+	}
+	if len(report.Warnings) != 1 {
+		t.Errorf("expected a warning about the ignored CODEOWNERS, but there were %d warnings", len(report.Warnings))
+	}
+	if report.TotalFilesCount != 2 || report.CoveredFilesCount != 1 {
+		t.Errorf("expected 1 of 2 files to be covered, but %d of %d were", report.CoveredFilesCount, report.TotalFilesCount)
+	}
+
+	err = report.setCoverageForRevision(repository, Options{Revision: "HEAD", Platform: PlatformGitLab})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// .github/CODEOWNERS is an ordinary file on GitLab, so it is counted and covered too
+	if report.CodeownersPath != "CODEOWNERS" || report.CoveredFilesCount != 3 {
+		t.Errorf("expected CODEOWNERS to be used on GitLab, covering 3 files, but %s was used, covering %d", report.CodeownersPath, report.CoveredFilesCount)
+	}
+}
+
+func TestSetCoverageDiagnostics(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS": "*.js @org/team_reviewers\n*.md not-an-owner\n",
+		"index.js":   "",
+	})
+
+	report := Report{}
+	err := report.setCoverageForRevision(repository, Options{Revision: "HEAD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, but there were %d", len(report.Diagnostics))
+	}
+	if diagnostic := report.Diagnostics[0].String(); !strings.HasPrefix(diagnostic, "CODEOWNERS:2:6: error: ") {
+		t.Errorf("expected diagnostic to be positioned at CODEOWNERS:2:6, but it was %s", diagnostic)
+	}
+}
+
+func TestSetCoverageForUnknownRevision(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitFiles(t, repository, fs, map[string]string{"CODEOWNERS": "* @org/everyone"})
+
+	report := Report{}
+	err := report.setCoverageForRevision(repository, Options{Revision: "does-not-exist"})
+	if err == nil {
+		t.Error("expected unknown revision to fail")
+	}
+}
+
 func TestToFormatWithData(t *testing.T) {
 	report := Report{
 		RemoteURL:         "https://github.com/gitignore/gitignore",
@@ -70,7 +316,7 @@ func TestToFormatWithoutData(t *testing.T) {
 
 func TestToFormatWithInvalidFormat(t *testing.T) {
 	report := Report{}
-	jsonString, err := report.ToFormat(reportFormat("dogs"))
+	jsonString, err := report.ToFormat(ReportFormat("dogs"))
 	if err == nil {
 		t.Error("Expected error that format is unsupported")
 	}
@@ -120,3 +366,34 @@ func createFile(path string, content []byte, fs billy.Filesystem, status *git.St
 	fileStatus.Staging = go_git.Unmodified
 	fileStatus.Worktree = go_git.Unmodified
 }
+
+func setupRepository(t *testing.T) (*go_git.Repository, billy.Filesystem) {
+	fs := memfs.New()
+	repository, err := go_git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repository, fs
+}
+
+func commitFiles(t *testing.T, repository *go_git.Repository, fs billy.Filesystem, files map[string]string) plumbing.Hash {
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		file, _ := fs.Create(path)
+		file.Write([]byte(content))
+		file.Close()
+		if _, err := worktree.Add(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hash, err := worktree.Commit("commit", &go_git.CommitOptions{
+		Author: &object.Signature{Name: "jeff", Email: "jeff@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
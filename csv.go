@@ -0,0 +1,103 @@
+package coverage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// writeFilesTable writes a header and then a row for each file, with its owners, the CODEOWNERS rule that
+// decided them, and whether it is covered
+func (r *Report) writeFilesTable(w io.Writer, comma rune) error {
+	table := newFilesTable(w, comma)
+	for _, file := range r.Files {
+		if err := table.WriteFile(file); err != nil {
+			return err
+		}
+	}
+	return table.Flush()
+}
+
+// FilesTable writes the table of files of ReportFormatCSV or ReportFormatTSV one row at a time. Given as
+// Options.FileHandler, with Options.OmitFiles, it writes each file as soon as its owners are resolved,
+// rather than holding every file in memory until the Report is complete.
+type FilesTable struct {
+	table       *csv.Writer
+	wroteHeader bool
+}
+
+// NewFilesTable creates a FilesTable that writes to w in the given format, which must be ReportFormatCSV
+// or ReportFormatTSV
+func NewFilesTable(w io.Writer, format ReportFormat) (*FilesTable, error) {
+	switch format {
+	case ReportFormatCSV:
+		return newFilesTable(w, ','), nil
+	case ReportFormatTSV:
+		return newFilesTable(w, '\t'), nil
+	default:
+		return nil, fmt.Errorf("%s is not a table of files", format)
+	}
+}
+
+// newFilesTable creates a FilesTable that separates fields with comma
+func newFilesTable(w io.Writer, comma rune) *FilesTable {
+	table := csv.NewWriter(w)
+	table.Comma = comma
+	return &FilesTable{table: table}
+}
+
+// WriteFile writes a row for the file, after the header if it has not been written yet
+func (t *FilesTable) WriteFile(file FileCoverage) error {
+	if err := t.writeHeader(); err != nil {
+		return err
+	}
+	var pattern, line string
+	if file.Rule != nil {
+		pattern = file.Rule.Pattern
+		line = strconv.FormatUint(file.Rule.LineNumber, 10)
+	}
+	return t.table.Write([]string{file.Path, strings.Join(file.Owners, " "), pattern, line, strconv.FormatBool(len(file.Owners) > 0)})
+}
+
+// Flush writes any buffered rows to the underlying io.Writer, and the header if no rows were written
+func (t *FilesTable) Flush() error {
+	if err := t.writeHeader(); err != nil {
+		return err
+	}
+	t.table.Flush()
+	return t.table.Error()
+}
+
+// writeHeader writes the header of the table, unless it has already been written
+func (t *FilesTable) writeHeader() error {
+	if t.wroteHeader {
+		return nil
+	}
+	t.wroteHeader = true
+	return t.table.Write([]string{"path", "owners", "pattern", "line", "covered"})
+}
+
+// writeOwnersTable writes a header and then a row for each owner, with the number and ratio of files they own
+// and the number of CODEOWNERS rules that name them
+func (r *Report) writeOwnersTable(w io.Writer, comma rune) error {
+	table := csv.NewWriter(w)
+	table.Comma = comma
+	if err := table.Write([]string{"owner", "files_count", "files_ratio", "rules_count"}); err != nil {
+		return err
+	}
+	for _, owner := range r.Owners {
+		row := []string{
+			owner.Owner,
+			strconv.Itoa(owner.FilesCount),
+			strconv.FormatFloat(owner.FilesRatio, 'f', -1, 64),
+			strconv.Itoa(owner.RulesCount),
+		}
+		if err := table.Write(row); err != nil {
+			return err
+		}
+	}
+	table.Flush()
+	return table.Error()
+}
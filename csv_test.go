@@ -0,0 +1,86 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToFormatCSV(t *testing.T) {
+	report := Report{
+		Files: []FileCoverage{
+			{Path: "src/main.go", Owners: []string{"@org/go", "dev@example.com"}, Rule: &RuleMatch{Pattern: "*.go", LineNumber: 4}},
+			{Path: "docs/a, b.md", Owners: []string{}},
+		},
+	}
+
+	csv, err := report.ToFormat(ReportFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "path,owners,pattern,line,covered\n" +
+		"src/main.go,@org/go dev@example.com,*.go,4,true\n" +
+		"\"docs/a, b.md\",,,,false\n"
+	if csv != expected {
+		t.Errorf("expected CSV\n%s\nbut got\n%s", expected, csv)
+	}
+
+	tsv, err := report.ToFormat(ReportFormatTSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "path\towners\tpattern\tline\tcovered\n" +
+		"src/main.go\t@org/go dev@example.com\t*.go\t4\ttrue\n" +
+		"docs/a, b.md\t\t\t\tfalse\n"
+	if tsv != expected {
+		t.Errorf("expected TSV\n%s\nbut got\n%s", expected, tsv)
+	}
+}
+
+func TestToFormatOwnersCSV(t *testing.T) {
+	report := Report{
+		Owners: []OwnerCoverage{
+			{Owner: "@org/go", FilesCount: 3, FilesRatio: 0.75, RulesCount: 2},
+			{Owner: "@org/docs", FilesCount: 0, FilesRatio: 0, RulesCount: 1},
+		},
+	}
+
+	csv, err := report.ToFormat(ReportFormatOwnersCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "owner,files_count,files_ratio,rules_count\n" +
+		"@org/go,3,0.75,2\n" +
+		"@org/docs,0,0,1\n"
+	if csv != expected {
+		t.Errorf("expected CSV\n%s\nbut got\n%s", expected, csv)
+	}
+}
+
+func TestWriteEndsWithNewline(t *testing.T) {
+	report := Report{}
+	var b strings.Builder
+	if err := report.Write(&b, ReportFormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if output := b.String(); !strings.HasPrefix(output, "{") || !strings.HasSuffix(output, "}\n") {
+		t.Errorf("expected JSON followed by a newline, but got %q", output)
+	}
+}
+
+func TestNewFilesTable(t *testing.T) {
+	var tsv strings.Builder
+	table, err := NewFilesTable(&tsv, ReportFormatTSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := table.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if tsv.String() != "path\towners\tpattern\tline\tcovered\n" {
+		t.Errorf("expected an empty table to have a header, but got %q", tsv.String())
+	}
+
+	if _, err := NewFilesTable(&tsv, ReportFormatOwnersCSV); err == nil {
+		t.Error("expected a table of owners to be rejected")
+	}
+}
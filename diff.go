@@ -0,0 +1,122 @@
+package coverage
+
+import (
+	"fmt"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+	"github.com/aaronsky/codeowners-coverage/internal/git"
+	go_git "gopkg.in/src-d/go-git.v4"
+)
+
+// DiffCoverage describes the files changed between two revisions, and who is required to review them
+type DiffCoverage struct {
+	// BaseSHA is the merge base of the base and head revisions, which the changes are relative to
+	BaseSHA string        `json:"base_sha"`
+	HeadSHA string        `json:"head_sha"`
+	Changes []ChangedFile `json:"changes"`
+}
+
+// ChangedFile is a file that was added, modified, renamed or deleted between two revisions
+type ChangedFile struct {
+	Path         string `json:"path"`
+	PreviousPath string `json:"previous_path,omitempty"`
+	Status       string `json:"status"`
+	Covered      bool   `json:"covered"`
+	// RequiredReviewers are the owners of the file's path and, if renamed, its previous path
+	RequiredReviewers []string `json:"required_reviewers"`
+}
+
+// setCoverageForDiff mutates the Report object to store the coverage of only the files that changed
+// between the merge base of Options.BaseRevision and Options.Revision, or HEAD if unset, and the latter.
+// As on GitHub, owners are read from the CODEOWNERS file of the base revision. Deleted files are listed
+// in the diff, but are not counted. Rules are still analyzed over every file of the head revision.
+func (r *Report) setCoverageForDiff(repository *go_git.Repository, options Options) error {
+	headRevision := options.Revision
+	if headRevision == "" {
+		headRevision = "HEAD"
+	}
+	base, err := git.ResolveCommit(repository, options.BaseRevision)
+	if err != nil {
+		return err
+	}
+	head, err := git.ResolveCommit(repository, headRevision)
+	if err != nil {
+		return err
+	}
+	r.SHA = head.Hash.String()
+
+	mergeBases, err := base.MergeBase(head)
+	if err != nil {
+		return err
+	}
+	if len(mergeBases) == 0 {
+		return fmt.Errorf("%s and %s have no common ancestor", options.BaseRevision, headRevision)
+	}
+	mergeBase := mergeBases[0]
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return err
+	}
+	mergeBaseTree, err := mergeBase.Tree()
+	if err != nil {
+		return err
+	}
+	headTree, err := head.Tree()
+	if err != nil {
+		return err
+	}
+
+	file, err := codeowners.LoadFileFromTree(baseTree, options.loadOptions())
+	if err != nil {
+		return err
+	}
+	r.setCodeownersFile(file)
+
+	changes, err := git.DiffTrees(mergeBaseTree, headTree)
+	if err != nil {
+		return err
+	}
+
+	diff := &DiffCoverage{BaseSHA: mergeBase.Hash.String(), HeadSHA: head.Hash.String(), Changes: []ChangedFile{}}
+	var paths []string
+	for _, change := range changes {
+		if isCodeowners(change.Path, file.Path, options.Platform) {
+			// skip codeowners
+			continue
+		}
+		if change.Action != git.ChangeDeleted {
+			paths = append(paths, change.Path)
+		}
+
+		owners := file.Codeowners.Owners(change.Path)
+		reviewers := owners
+		if change.PreviousPath != "" {
+			reviewers = uniqueStrings(append(append([]string{}, owners...), file.Codeowners.Owners(change.PreviousPath)...))
+		}
+		diff.Changes = append(diff.Changes, ChangedFile{
+			Path:              change.Path,
+			PreviousPath:      change.PreviousPath,
+			Status:            string(change.Action),
+			Covered:           len(owners) > 0,
+			RequiredReviewers: reviewers,
+		})
+	}
+
+	if err := r.setCoverageForPaths(paths, file.Codeowners, options); err != nil {
+		return err
+	}
+	// Rules are analyzed over every file of the head revision, since a rule that matches none of the changed
+	// files is not dead
+	headPaths, err := git.TreeFiles(headTree)
+	if err != nil {
+		return err
+	}
+	analysis := codeowners.NewAnalysis(file.Codeowners)
+	for _, path := range withoutCodeowners(headPaths, file.Path, options.Platform) {
+		analysis.Add(path)
+	}
+	r.Rules = newRulesCoverage(analysis)
+	r.Diff = diff
+	return nil
+}
@@ -0,0 +1,151 @@
+package coverage
+
+import (
+	"encoding/json"
+	"testing"
+
+	go_git "gopkg.in/src-d/go-git.v4"
+)
+
+func TestSetCoverageForDiff(t *testing.T) {
+	repository, fs := setupRepository(t)
+	base := commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS":   "*.js @org/frontend\nlegacy/ @org/legacy\n",
+		"index.js":     "index",
+		"README.md":    "readme",
+		"legacy/a.rb":  "moving",
+		"unchanged.js": "unchanged",
+	})
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Remove("legacy/a.rb"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := worktree.Remove("README.md"); err != nil {
+		t.Fatal(err)
+	}
+	commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS": "* @org/everyone\n",
+		"index.js":   "index, modified",
+		"src/b.rb":   "moving",
+		"notes.txt":  "new",
+	})
+
+	report := Report{}
+	err = report.setCoverageForDiff(repository, Options{BaseRevision: base.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Diff == nil {
+		t.Fatal("expected diff to be set")
+	}
+	if report.Diff.BaseSHA != base.String() || report.Diff.HeadSHA != report.SHA {
+		t.Errorf("expected diff to be between %s and %s, but it was between %s and %s", base, report.SHA, report.Diff.BaseSHA, report.Diff.HeadSHA)
+	}
+	if report.TotalFilesCount != 3 {
+		t.Errorf("expected only the 3 changed files that still exist to be counted, but %d were", report.TotalFilesCount)
+	}
+	if report.CoveredFilesCount != 1 {
+		t.Errorf("expected 1 changed file to be covered by the base CODEOWNERS, but %d were", report.CoveredFilesCount)
+	}
+
+	expected := []ChangedFile{
+		{Path: "README.md", Status: "deleted", Covered: false, RequiredReviewers: []string{}},
+		{Path: "index.js", Status: "modified", Covered: true, RequiredReviewers: []string{"@org/frontend"}},
+		{Path: "notes.txt", Status: "added", Covered: false, RequiredReviewers: []string{}},
+		{Path: "src/b.rb", PreviousPath: "legacy/a.rb", Status: "renamed", Covered: false, RequiredReviewers: []string{"@org/legacy"}},
+	}
+	if len(report.Diff.Changes) != len(expected) {
+		t.Fatalf("expected %d changes, but there were %+v", len(expected), report.Diff.Changes)
+	}
+	for i, change := range report.Diff.Changes {
+		e := expected[i]
+		if change.Path != e.Path || change.PreviousPath != e.PreviousPath || change.Status != e.Status || change.Covered != e.Covered {
+			t.Errorf("expected change %d to be %+v, but it was %+v", i, e, change)
+		}
+		if len(change.RequiredReviewers) != len(e.RequiredReviewers) || (len(e.RequiredReviewers) > 0 && change.RequiredReviewers[0] != e.RequiredReviewers[0]) {
+			t.Errorf("expected %s to require %v, but it required %v", change.Path, e.RequiredReviewers, change.RequiredReviewers)
+		}
+	}
+}
+
+func TestSetCoverageForDiffFromMergeBase(t *testing.T) {
+	repository, fs := setupRepository(t)
+	branchPoint := commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS": "*.js @org/frontend\n",
+		"index.js":   "index",
+	})
+	head := commitFiles(t, repository, fs, map[string]string{
+		"feature.go": "feature",
+	})
+
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := worktree.Checkout(&go_git.CheckoutOptions{Hash: branchPoint}); err != nil {
+		t.Fatal(err)
+	}
+	base := commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS": "* @org/everyone\n",
+		"main.go":    "only on base",
+	})
+
+	report := Report{}
+	err = report.setCoverageForDiff(repository, Options{BaseRevision: base.String(), Revision: head.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Diff.BaseSHA != branchPoint.String() {
+		t.Errorf("expected the diff to start from the merge base %s, but it started from %s", branchPoint, report.Diff.BaseSHA)
+	}
+	if len(report.Diff.Changes) != 1 || report.Diff.Changes[0].Path != "feature.go" {
+		t.Fatalf("expected only feature.go to have changed since the merge base, but the changes were %+v", report.Diff.Changes)
+	}
+	if !report.Diff.Changes[0].Covered {
+		t.Error("expected feature.go to be covered by the CODEOWNERS file of the base revision")
+	}
+}
+
+func TestSetCoverageForDiffRules(t *testing.T) {
+	repository, fs := setupRepository(t)
+	base := commitFiles(t, repository, fs, map[string]string{
+		"CODEOWNERS": "*.go @org/go\n*.md @org/docs\n*.rb @org/ruby\n*.py @org/python\n",
+		"main.go":    "main",
+		"README.md":  "readme",
+		"app.rb":     "app",
+	})
+	commitFiles(t, repository, fs, map[string]string{
+		"notes.txt": "new",
+	})
+
+	report := Report{}
+	err := report.setCoverageForDiff(repository, Options{BaseRevision: base.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Rules) != 4 {
+		t.Fatalf("expected 4 rules, but there were %+v", report.Rules)
+	}
+	for _, rule := range report.Rules {
+		if rule.Dead() != (rule.Pattern == "*.py") {
+			t.Errorf("expected only *.py to be dead in the head revision, but %s was dead: %t", rule.Pattern, rule.Dead())
+		}
+	}
+
+	output, err := report.ToFormat(ReportFormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatal(err)
+	}
+	results := log.Runs[0].Results
+	if len(results) != 1 || results[0].RuleID != "unowned-file" {
+		t.Errorf("expected only the changed, unowned file to be reported, and no rules, but got %+v", results)
+	}
+}
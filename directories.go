@@ -0,0 +1,104 @@
+package coverage
+
+import (
+	"sort"
+	"strings"
+)
+
+// DirectoryCoverage contains coverage information for a directory and, recursively, its subdirectories
+type DirectoryCoverage struct {
+	Path              string               `json:"path"`
+	CoveredFilesCount int                  `json:"covered_files_count"`
+	TotalFilesCount   int                  `json:"total_files_count"`
+	CoverageRatio     float64              `json:"coverage_ratio"`
+	DominantOwner     string               `json:"dominant_owner,omitempty"`
+	Children          []*DirectoryCoverage `json:"children,omitempty"`
+
+	ownerCounts map[string]int
+}
+
+// newDirectoryCoverage builds a tree of directories rooted at "." from the given files. Directories deeper
+// than maxDepth are not given their own node, but their files still count towards their ancestors.
+// A maxDepth of 0 or less places no limit on the depth of the tree.
+func newDirectoryCoverage(files []FileCoverage, maxDepth int) *DirectoryCoverage {
+	tree := newDirectoryTree(maxDepth)
+	for _, file := range files {
+		tree.add(file)
+	}
+	return tree.finish()
+}
+
+// directoryTree builds a tree of directories as files are resolved, so that the files need not be kept
+type directoryTree struct {
+	root     *DirectoryCoverage
+	nodes    map[string]*DirectoryCoverage
+	maxDepth int
+}
+
+// newDirectoryTree creates a tree with only the root directory, limited to maxDepth as in newDirectoryCoverage
+func newDirectoryTree(maxDepth int) *directoryTree {
+	root := &DirectoryCoverage{Path: ".", ownerCounts: map[string]int{}}
+	return &directoryTree{root: root, nodes: map[string]*DirectoryCoverage{".": root}, maxDepth: maxDepth}
+}
+
+// add counts the file towards each of the directories that contain it, creating them as needed
+func (t *directoryTree) add(file FileCoverage) {
+	segments := strings.Split(file.Path, "/")
+	dirs := segments[:len(segments)-1]
+	if t.maxDepth > 0 && len(dirs) > t.maxDepth {
+		dirs = dirs[:t.maxDepth]
+	}
+
+	t.root.add(file)
+	parent := t.root
+	for i := range dirs {
+		path := strings.Join(dirs[:i+1], "/")
+		node, ok := t.nodes[path]
+		if !ok {
+			node = &DirectoryCoverage{Path: path, ownerCounts: map[string]int{}}
+			t.nodes[path] = node
+			parent.Children = append(parent.Children, node)
+		}
+		node.add(file)
+		parent = node
+	}
+}
+
+// finish computes the ratios and dominant owners of the tree, and returns its root
+func (t *directoryTree) finish() *DirectoryCoverage {
+	t.root.finalize()
+	return t.root
+}
+
+// add counts the given file towards the coverage of the directory
+func (d *DirectoryCoverage) add(file FileCoverage) {
+	d.TotalFilesCount++
+	if len(file.Owners) > 0 {
+		d.CoveredFilesCount++
+	}
+	for _, owner := range uniqueStrings(file.Owners) {
+		d.ownerCounts[owner]++
+	}
+}
+
+// finalize computes the ratio and dominant owner of the directory and its children, and sorts children by path
+func (d *DirectoryCoverage) finalize() {
+	if d.TotalFilesCount > 0 {
+		d.CoverageRatio = float64(d.CoveredFilesCount) / float64(d.TotalFilesCount)
+	}
+	var dominantCount int
+	for owner, count := range d.ownerCounts {
+		if count > dominantCount || (count == dominantCount && owner < d.DominantOwner) {
+			d.DominantOwner = owner
+			dominantCount = count
+		}
+	}
+	d.ownerCounts = nil
+
+	sort.Slice(d.Children, func(i, j int) bool {
+		return d.Children[i].Path < d.Children[j].Path
+	})
+	for _, child := range d.Children {
+		child.finalize()
+	}
+}
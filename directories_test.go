@@ -0,0 +1,56 @@
+package coverage
+
+import (
+	"testing"
+)
+
+func TestNewDirectoryCoverage(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "README.md", Owners: []string{}},
+		{Path: "services/api/main.go", Owners: []string{"@org/api"}},
+		{Path: "services/api/handler.go", Owners: []string{"@org/api"}},
+		{Path: "services/web/index.js", Owners: []string{"@org/web"}},
+		{Path: "legacy/old.c", Owners: []string{}},
+	}
+	root := newDirectoryCoverage(files, 0)
+	if root.Path != "." {
+		t.Errorf("expected root path to be '.', but it was '%s'", root.Path)
+	}
+	if root.TotalFilesCount != 5 || root.CoveredFilesCount != 3 {
+		t.Errorf("expected root to have 3 of 5 files covered, but it had %d of %d", root.CoveredFilesCount, root.TotalFilesCount)
+	}
+	if root.DominantOwner != "@org/api" {
+		t.Errorf("expected root dominant owner to be @org/api, but it was %s", root.DominantOwner)
+	}
+	if len(root.Children) != 2 || root.Children[0].Path != "legacy" || root.Children[1].Path != "services" {
+		t.Fatalf("expected root children to be [legacy services], but they were %+v", root.Children)
+	}
+	legacy, services := root.Children[0], root.Children[1]
+	if legacy.CoverageRatio != 0 || legacy.DominantOwner != "" {
+		t.Errorf("expected legacy to be unowned, but it had ratio %f and dominant owner '%s'", legacy.CoverageRatio, legacy.DominantOwner)
+	}
+	if services.CoverageRatio != 1 {
+		t.Errorf("expected services to be fully owned, but it had ratio %f", services.CoverageRatio)
+	}
+	if len(services.Children) != 2 || services.Children[0].Path != "services/api" || services.Children[0].TotalFilesCount != 2 {
+		t.Errorf("expected services/api to contain 2 files, but children were %+v", services.Children)
+	}
+}
+
+func TestNewDirectoryCoverageMaxDepth(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "a/b/c/d.go", Owners: []string{"@one"}},
+		{Path: "a/b/e.go", Owners: []string{}},
+	}
+	root := newDirectoryCoverage(files, 1)
+	if len(root.Children) != 1 {
+		t.Fatalf("expected 1 child of root, but there were %d", len(root.Children))
+	}
+	a := root.Children[0]
+	if len(a.Children) != 0 {
+		t.Errorf("expected no directories below max depth, but there were %d", len(a.Children))
+	}
+	if a.TotalFilesCount != 2 || a.CoveredFilesCount != 1 {
+		t.Errorf("expected 'a' to have 1 of 2 files covered, but it had %d of %d", a.CoveredFilesCount, a.TotalFilesCount)
+	}
+}
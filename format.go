@@ -0,0 +1,193 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ReportFormat is the name of a format that a Report can be written in
+type ReportFormat string
+
+const (
+	// ReportFormatJSON is a constant representing the JSON format for a Report object
+	ReportFormatJSON ReportFormat = "json"
+	// ReportFormatMarkdown is a constant representing the Markdown format for a Report object
+	ReportFormatMarkdown ReportFormat = "markdown"
+	// ReportFormatHTML is a constant representing the self-contained HTML page format for a Report object
+	ReportFormatHTML ReportFormat = "html"
+	// ReportFormatJUnit is a constant representing the JUnit XML format for a Report object, with a test case per file
+	ReportFormatJUnit ReportFormat = "junit"
+	// ReportFormatJUnitDirectories is a constant representing the JUnit XML format for a Report object, with a test
+	// case per directory
+	ReportFormatJUnitDirectories ReportFormat = "junit-directories"
+	// ReportFormatSARIF is a constant representing the SARIF 2.1.0 format for a Report object, as used by code scanning
+	ReportFormatSARIF ReportFormat = "sarif"
+	// ReportFormatCSV is a constant representing a comma-separated table of the files of a Report object
+	ReportFormatCSV ReportFormat = "csv"
+	// ReportFormatTSV is a constant representing a tab-separated table of the files of a Report object
+	ReportFormatTSV ReportFormat = "tsv"
+	// ReportFormatOwnersCSV is a constant representing a comma-separated table of the owners of a Report object
+	ReportFormatOwnersCSV ReportFormat = "owners-csv"
+	// ReportFormatOwnersTSV is a constant representing a tab-separated table of the owners of a Report object
+	ReportFormatOwnersTSV ReportFormat = "owners-tsv"
+	// ReportFormatPrometheus is a constant representing the Prometheus text exposition format for a Report object
+	ReportFormatPrometheus ReportFormat = "prometheus"
+	// ReportFormatBadgeSVG is a constant representing an SVG badge of the coverage ratio of a Report object. It is
+	// not registered, since badges depend on BadgeOptions; use NewBadgeFormatter instead.
+	ReportFormatBadgeSVG ReportFormat = "badge"
+	// ReportFormatBadgeEndpoint is a constant representing the coverage ratio of a Report object as JSON for a
+	// Shields endpoint badge. It is not registered, since badges depend on BadgeOptions; use
+	// NewBadgeEndpointFormatter instead.
+	ReportFormatBadgeEndpoint ReportFormat = "badge-endpoint"
+)
+
+// Formatter writes a Report in a particular format
+type Formatter interface {
+	Format(w io.Writer, r *Report) error
+}
+
+// FormatterFunc is an adapter that allows an ordinary function to be used as a Formatter
+type FormatterFunc func(w io.Writer, r *Report) error
+
+// Format calls f(w, r)
+func (f FormatterFunc) Format(w io.Writer, r *Report) error {
+	return f(w, r)
+}
+
+var (
+	formattersMu sync.RWMutex
+	formatters   = map[ReportFormat]Formatter{}
+)
+
+func init() {
+	RegisterFormat(ReportFormatJSON, FormatterFunc(func(w io.Writer, r *Report) error {
+		bytes, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes)
+		return err
+	}))
+	RegisterFormat(ReportFormatMarkdown, stringFormatter(func(r *Report) (string, error) {
+		return r.toMarkdown(), nil
+	}))
+	RegisterFormat(ReportFormatHTML, stringFormatter((*Report).toHTML))
+	RegisterFormat(ReportFormatJUnit, stringFormatter((*Report).toJUnit))
+	RegisterFormat(ReportFormatJUnitDirectories, stringFormatter((*Report).toJUnitDirectories))
+	RegisterFormat(ReportFormatSARIF, stringFormatter((*Report).toSARIF))
+	RegisterFormat(ReportFormatCSV, FormatterFunc(func(w io.Writer, r *Report) error {
+		return r.writeFilesTable(w, ',')
+	}))
+	RegisterFormat(ReportFormatTSV, FormatterFunc(func(w io.Writer, r *Report) error {
+		return r.writeFilesTable(w, '\t')
+	}))
+	RegisterFormat(ReportFormatOwnersCSV, FormatterFunc(func(w io.Writer, r *Report) error {
+		return r.writeOwnersTable(w, ',')
+	}))
+	RegisterFormat(ReportFormatOwnersTSV, FormatterFunc(func(w io.Writer, r *Report) error {
+		return r.writeOwnersTable(w, '\t')
+	}))
+	RegisterFormat(ReportFormatPrometheus, stringFormatter(func(r *Report) (string, error) {
+		return r.toPrometheus(), nil
+	}))
+}
+
+// RegisterFormat makes a Formatter available by the given name to ToFormat, Write and the CLI's --format flag.
+// It panics if the name is already registered or the Formatter is nil.
+func RegisterFormat(name ReportFormat, formatter Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	if formatter == nil {
+		panic("coverage: RegisterFormat formatter is nil")
+	}
+	if _, ok := formatters[name]; ok {
+		panic(fmt.Sprintf("coverage: RegisterFormat called twice for format %q", name))
+	}
+	formatters[name] = formatter
+}
+
+// Formats returns the names of the registered formats, sorted alphabetically
+func Formats() []ReportFormat {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	names := make([]ReportFormat, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// lookupFormatter returns the Formatter registered with the given name
+func lookupFormatter(name ReportFormat) (Formatter, error) {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	formatter, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported report format %q", name)
+	}
+	return formatter, nil
+}
+
+// ToFormat converts the report to a string in the given format.
+func (r *Report) ToFormat(format ReportFormat) (string, error) {
+	formatter, err := lookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := formatter.Format(&b, r); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// Write writes the report to w in the given format, ending with a newline. Formatters that write as they go,
+// such as those of tables, do not build their whole output in memory. To write files before the report is
+// complete, see FilesTable.
+func (r *Report) Write(w io.Writer, format ReportFormat) error {
+	formatter, err := lookupFormatter(format)
+	if err != nil {
+		return err
+	}
+	tracked := &lastByteWriter{w: w}
+	if err := formatter.Format(tracked, r); err != nil {
+		return err
+	}
+	if tracked.last != '\n' {
+		_, err = io.WriteString(w, "\n")
+	}
+	return err
+}
+
+// stringFormatter adapts a function that renders a report as a string to a Formatter
+func stringFormatter(fn func(*Report) (string, error)) Formatter {
+	return FormatterFunc(func(w io.Writer, r *Report) error {
+		output, err := fn(r)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, output)
+		return err
+	})
+}
+
+// lastByteWriter is an io.Writer that remembers the last byte written through it
+type lastByteWriter struct {
+	w    io.Writer
+	last byte
+}
+
+func (w *lastByteWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	if n > 0 {
+		w.last = p[n-1]
+	}
+	return n, err
+}
@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRegisterFormat(t *testing.T) {
+	format := ReportFormat("test-ratio")
+	RegisterFormat(format, FormatterFunc(func(w io.Writer, r *Report) error {
+		_, err := fmt.Fprintf(w, "%d/%d", r.CoveredFilesCount, r.TotalFilesCount)
+		return err
+	}))
+
+	registered := false
+	for _, name := range Formats() {
+		if name == format {
+			registered = true
+		}
+	}
+	if !registered {
+		t.Errorf("expected %s to be listed in %v", format, Formats())
+	}
+
+	report := Report{CoveredFilesCount: 1, TotalFilesCount: 2}
+	output, err := report.ToFormat(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "1/2" {
+		t.Errorf("expected the registered formatter to be used, but got %q", output)
+	}
+
+	var b strings.Builder
+	if err := report.Write(&b, format); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "1/2\n" {
+		t.Errorf("expected Write to end the output with a newline, but got %q", b.String())
+	}
+}
+
+func TestRegisterFormatTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected registering a format twice to panic")
+		}
+	}()
+	RegisterFormat(ReportFormatJSON, FormatterFunc(func(w io.Writer, r *Report) error {
+		return nil
+	}))
+}
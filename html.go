@@ -0,0 +1,217 @@
+package coverage
+
+import (
+	"html/template"
+	"strings"
+)
+
+// toHTML renders the report as a single HTML page with no external assets. The page shows a collapsible tree
+// of directories colored by coverage, which can be filtered by owner or searched by path, and selecting a file
+// shows the CODEOWNERS rule that decided its owners.
+func (r *Report) toHTML() (string, error) {
+	var b strings.Builder
+	if err := htmlReportTemplate.Execute(&b, r); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// htmlReportTemplate embeds the report as JSON, from which the page's script builds the directory tree. The tree
+// is built from the files rather than the report's directories, which may be limited in depth.
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>CODEOWNERS coverage{{if .RemoteURL}} of {{.RemoteURL}}{{end}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { font-size: 1.5em; }
+code, .tree { font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 0.9em; }
+.summary td, .summary th { text-align: left; padding: 0.2em 1em 0.2em 0; }
+.controls { margin: 1em 0; }
+.controls input { width: 24em; }
+.layout { display: flex; align-items: flex-start; gap: 2em; }
+.tree { flex: 1; }
+.tree ul { list-style: none; margin: 0; padding-left: 1.5em; }
+.tree summary { cursor: pointer; }
+.tree .file { cursor: pointer; }
+.tree .file:hover, .tree .file.selected { text-decoration: underline; }
+.ratio { color: #586069; }
+.high { color: #22863a; }
+.medium { color: #b08800; }
+.low { color: #cb2431; }
+.details { flex: 1; position: sticky; top: 1em; border: 1px solid #e1e4e8; border-radius: 6px; padding: 1em; }
+</style>
+</head>
+<body>
+<h1>CODEOWNERS coverage</h1>
+<table class="summary">
+<tr><th>Repository</th><td><code>{{.RemoteURL}}</code></td></tr>
+<tr><th>Commit</th><td><code>{{.SHA}}</code></td></tr>
+<tr><th>CODEOWNERS</th><td><code>{{.CodeownersPath}}</code></td></tr>
+<tr><th>Covered files</th><td>{{.CoveredFilesCount}} of {{.TotalFilesCount}}</td></tr>
+</table>
+<div class="controls">
+<label>Owner <select id="owner"><option value="">All owners</option><option value="(none)">No owner</option></select></label>
+<label>Search <input id="search" type="search" placeholder="Filter by path"></label>
+</div>
+<div class="layout">
+<div class="tree" id="tree"></div>
+<div class="details" id="details">Select a file to see the CODEOWNERS rule that decided its owners.</div>
+</div>
+<script>
+(function () {
+  "use strict";
+  var report = {{.}};
+  var files = report.files || [];
+  files.forEach(function (file) {
+    file.owners = file.owners || [];
+  });
+  var ownerSelect = document.getElementById("owner");
+  var search = document.getElementById("search");
+  var tree = document.getElementById("tree");
+  var details = document.getElementById("details");
+  var selected = null;
+
+  (report.owners || []).forEach(function (owner) {
+    var option = document.createElement("option");
+    option.value = owner.owner;
+    option.textContent = owner.owner + " (" + owner.files_count + ")";
+    ownerSelect.appendChild(option);
+  });
+
+  function element(tag, className, text) {
+    var el = document.createElement(tag);
+    if (className) {
+      el.className = className;
+    }
+    if (text !== undefined) {
+      el.textContent = text;
+    }
+    return el;
+  }
+
+  function percent(ratio) {
+    return (ratio * 100).toFixed(1) + "%";
+  }
+
+  function band(ratio) {
+    if (ratio >= 0.9) {
+      return "high";
+    }
+    return ratio >= 0.5 ? "medium" : "low";
+  }
+
+  function newDirectory(name, path) {
+    return { name: name, path: path, directories: {}, files: [], covered: 0, total: 0 };
+  }
+
+  function count(directory, file) {
+    directory.total++;
+    if (file.owners.length > 0) {
+      directory.covered++;
+    }
+  }
+
+  function buildTree(list) {
+    var root = newDirectory(".", ".");
+    list.forEach(function (file) {
+      var segments = file.path.split("/");
+      var directory = root;
+      count(directory, file);
+      for (var i = 0; i < segments.length - 1; i++) {
+        var name = segments[i];
+        if (!directory.directories.hasOwnProperty(name)) {
+          directory.directories[name] = newDirectory(name, segments.slice(0, i + 1).join("/"));
+        }
+        directory = directory.directories[name];
+        count(directory, file);
+      }
+      directory.files.push(file);
+    });
+    return root;
+  }
+
+  function renderFile(file) {
+    var item = element("li", "file " + (file.owners.length > 0 ? "high" : "low"), file.path.split("/").pop());
+    item.title = file.path;
+    item.addEventListener("click", function () {
+      if (selected) {
+        selected.classList.remove("selected");
+      }
+      selected = item;
+      item.classList.add("selected");
+      showFile(file);
+    });
+    return item;
+  }
+
+  function renderDirectory(directory, open) {
+    var ratio = directory.covered / directory.total;
+    var el = element("details");
+    el.open = open;
+    var summary = element("summary", band(ratio), directory.name + "/ ");
+    summary.appendChild(element("span", "ratio", percent(ratio) + " (" + directory.covered + "/" + directory.total + ")"));
+    el.appendChild(summary);
+
+    var list = element("ul");
+    Object.keys(directory.directories).sort().forEach(function (name) {
+      var item = element("li");
+      item.appendChild(renderDirectory(directory.directories[name], open));
+      list.appendChild(item);
+    });
+    directory.files.forEach(function (file) {
+      list.appendChild(renderFile(file));
+    });
+    el.appendChild(list);
+    return el;
+  }
+
+  function showFile(file) {
+    details.textContent = "";
+    details.appendChild(element("h2", "", file.path));
+    details.appendChild(element("p", "", file.owners.length > 0 ? "Owners: " + file.owners.join(" ") : "No owners"));
+    if (file.rule) {
+      var rule = element("p", "", "Decided by " + (report.codeowners_path || "CODEOWNERS") + " line " + file.rule.line_number + ": ");
+      rule.appendChild(element("code", "", file.rule.pattern + " " + file.owners.join(" ")));
+      details.appendChild(rule);
+    } else {
+      details.appendChild(element("p", "", "No CODEOWNERS rule matches this file."));
+    }
+  }
+
+  function matches(file, owner, query) {
+    if (owner === "(none)" && file.owners.length > 0) {
+      return false;
+    }
+    if (owner !== "" && owner !== "(none)" && file.owners.indexOf(owner) < 0) {
+      return false;
+    }
+    return file.path.toLowerCase().indexOf(query) >= 0;
+  }
+
+  function render() {
+    var owner = ownerSelect.value;
+    var query = search.value.toLowerCase();
+    var list = files.filter(function (file) {
+      return matches(file, owner, query);
+    });
+    tree.textContent = "";
+    if (list.length === 0) {
+      tree.textContent = "No files match.";
+      return;
+    }
+    var root = renderDirectory(buildTree(list), owner !== "" || query !== "");
+    root.open = true;
+    tree.appendChild(root);
+  }
+
+  ownerSelect.addEventListener("change", render);
+  search.addEventListener("input", render);
+  render();
+})();
+</script>
+</body>
+</html>
+`))
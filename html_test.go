@@ -0,0 +1,40 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToFormatHTML(t *testing.T) {
+	report := Report{
+		RemoteURL:         "git@github.com:org/repo.git",
+		SHA:               "abc123",
+		CoveredFilesCount: 1,
+		TotalFilesCount:   2,
+		CoverageRatio:     0.5,
+		CodeownersPath:    ".github/CODEOWNERS",
+		Files: []FileCoverage{
+			{Path: "src/</script><script>alert(1)</script>.js", Owners: []string{}},
+			{Path: "src/main.go", Owners: []string{"@org/go"}, Rule: &RuleMatch{Pattern: "*.go", LineNumber: 3}},
+		},
+	}
+
+	html, err := report.ToFormat(ReportFormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(html, "<!DOCTYPE html>") {
+		t.Error("expected an HTML document")
+	}
+	if !strings.Contains(html, `"line_number":3`) {
+		t.Error("expected the report to be embedded in the page")
+	}
+	if strings.Contains(html, "<script>alert(1)") {
+		t.Error("expected paths to be escaped within the page's script")
+	}
+	for _, external := range []string{"<link", "<script src", "<img", "url("} {
+		if strings.Contains(html, external) {
+			t.Errorf("expected no external assets, but found %q", external)
+		}
+	}
+}
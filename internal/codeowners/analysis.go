@@ -0,0 +1,106 @@
+package codeowners
+
+// Analysis accumulates statistics on how each entry of a Codeowners applies to a set of paths
+type Analysis struct {
+	codeowners Codeowners
+	matched    []int
+	decided    []int
+	// shadowers holds, for each entry, the indices of later entries that have matched every path
+	// the entry has matched so far. It is nil until the entry matches its first path.
+	shadowers [][]int
+}
+
+// RuleStats describes how a single entry of a Codeowners applied to the paths in an Analysis
+type RuleStats struct {
+	Entry OwnerEntry
+	// MatchedCount is the number of paths the entry's pattern matched
+	MatchedCount int
+	// DecidedCount is the number of paths whose ownership was decided by the entry, being the last match
+	DecidedCount int
+	// ShadowedBy is a later entry that matches every path this entry matches, if one exists
+	ShadowedBy *OwnerEntry
+}
+
+// Dead returns whether or not the entry matched no paths at all
+func (s RuleStats) Dead() bool {
+	return s.MatchedCount == 0
+}
+
+// Shadowed returns whether or not the entry matched paths, but later entries decided all of them
+func (s RuleStats) Shadowed() bool {
+	return s.MatchedCount > 0 && s.DecidedCount == 0
+}
+
+// NewAnalysis creates an empty Analysis for the given Codeowners
+func NewAnalysis(o Codeowners) *Analysis {
+	return &Analysis{
+		codeowners: o,
+		matched:    make([]int, len(o)),
+		decided:    make([]int, len(o)),
+		shadowers:  make([][]int, len(o)),
+	}
+}
+
+// Add evaluates every entry against the given path, and returns the entry that decides its ownership,
+// or nil if no entry matches.
+func (a *Analysis) Add(path string) *OwnerEntry {
+	var matches []int
+	for i := range a.codeowners {
+		if a.codeowners[i].Pattern.Matches(path) {
+			matches = append(matches, i)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	for k, i := range matches {
+		a.matched[i]++
+		later := matches[k+1:]
+		if a.shadowers[i] == nil {
+			a.shadowers[i] = append([]int{}, later...)
+		} else {
+			a.shadowers[i] = intersectSorted(a.shadowers[i], later)
+		}
+	}
+	last := matches[len(matches)-1]
+	a.decided[last]++
+
+	return &a.codeowners[last]
+}
+
+// Rules returns the statistics of every entry, in the order they appear in the CODEOWNERS file
+func (a *Analysis) Rules() []RuleStats {
+	stats := make([]RuleStats, len(a.codeowners))
+	for i, entry := range a.codeowners {
+		stats[i] = RuleStats{
+			Entry:        entry,
+			MatchedCount: a.matched[i],
+			DecidedCount: a.decided[i],
+		}
+		// Prefer the last shadowing entry, since it is the one most likely to be deciding ownership.
+		if shadowers := a.shadowers[i]; len(shadowers) > 0 {
+			stats[i].ShadowedBy = &a.codeowners[shadowers[len(shadowers)-1]]
+		}
+	}
+	return stats
+}
+
+// intersectSorted returns the values present in both of the given ascending slices
+func intersectSorted(a, b []int) []int {
+	intersection := a[:0]
+	var i, j int
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			intersection = append(intersection, a[i])
+			i++
+			j++
+		}
+	}
+	return intersection
+}
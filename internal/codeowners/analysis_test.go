@@ -0,0 +1,61 @@
+package codeowners
+
+import (
+	"testing"
+
+	"gopkg.in/src-d/go-billy.v4/memfs"
+)
+
+func TestAnalysis(t *testing.T) {
+	mockFs := memfs.New()
+	file, _ := mockFs.Create("CODEOWNERS")
+	file.Write([]byte(`*.js		@org/frontend
+*.go		@org/backend
+*.rb		@org/rubyists
+*		@org/everyone
+*.md		@org/docs`))
+	owners, err := LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis := NewAnalysis(owners)
+	for _, path := range []string{"index.js", "src/app.js", "main.go", "README.md", "Makefile"} {
+		if entry := analysis.Add(path); entry == nil {
+			t.Errorf("expected an entry to decide '%s'", path)
+		}
+	}
+	stats := analysis.Rules()
+	if len(stats) != 5 {
+		t.Fatalf("expected 5 rule stats, but there were %d", len(stats))
+	}
+
+	js := stats[0]
+	if js.MatchedCount != 2 || js.DecidedCount != 0 {
+		t.Errorf("expected *.js to match 2 and decide 0, but it matched %d and decided %d", js.MatchedCount, js.DecidedCount)
+	}
+	if !js.Shadowed() || js.ShadowedBy == nil || js.ShadowedBy.LineNumber() != 4 {
+		t.Errorf("expected *.js to be shadowed by line 4, but it was shadowed by %v", js.ShadowedBy)
+	}
+
+	rb := stats[2]
+	if !rb.Dead() {
+		t.Errorf("expected *.rb to be dead, but it matched %d", rb.MatchedCount)
+	}
+	if rb.ShadowedBy != nil {
+		t.Errorf("expected *.rb to not be shadowed, but it was shadowed by %v", rb.ShadowedBy)
+	}
+
+	everyone := stats[3]
+	if everyone.MatchedCount != 5 || everyone.DecidedCount != 4 {
+		t.Errorf("expected * to match 5 and decide 4, but it matched %d and decided %d", everyone.MatchedCount, everyone.DecidedCount)
+	}
+	if everyone.Shadowed() || everyone.ShadowedBy != nil {
+		t.Error("expected * to not be shadowed")
+	}
+
+	md := stats[4]
+	if md.MatchedCount != 1 || md.DecidedCount != 1 {
+		t.Errorf("expected *.md to match 1 and decide 1, but it matched %d and decided %d", md.MatchedCount, md.DecidedCount)
+	}
+}
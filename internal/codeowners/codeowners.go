@@ -14,7 +14,6 @@
 package codeowners
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -22,21 +21,63 @@ import (
 
 	"github.com/aaronsky/codeowners-coverage/internal/git"
 	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
-var codeownersDirectories = []string{".", "docs", ".github"}
+// Platform identifies a code hosting service, which determines where CODEOWNERS files are looked for
+type Platform string
 
-// PathIsCodeowners returns whether or not the provided path is for a valid CODEOWNERS file
+const (
+	// PlatformGitHub looks for CODEOWNERS in the .github/, root, and docs/ directories, in that order
+	// see: https://help.github.com/articles/about-code-owners/#codeowners-file-location
+	PlatformGitHub Platform = "github"
+	// PlatformGitLab looks for CODEOWNERS in the root, docs/, and .gitlab/ directories, in that order
+	// see: https://docs.gitlab.com/ee/user/project/code_owners.html
+	PlatformGitLab Platform = "gitlab"
+)
+
+// codeownersLocations lists, for each platform in order of precedence, the paths a CODEOWNERS file may be read from
+var codeownersLocations = map[Platform][]string{
+	PlatformGitHub: {".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"},
+	PlatformGitLab: {"CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"},
+}
+
+// Platforms returns the names of all supported platforms
+func Platforms() []Platform {
+	return []Platform{PlatformGitHub, PlatformGitLab}
+}
+
+// PathIsCodeowners returns whether or not the provided path is for a valid CODEOWNERS file on the given platform,
+// which defaults to PlatformGitHub
 // see: https://help.github.com/articles/about-code-owners/#codeowners-file-location
-func PathIsCodeowners(path string, fs billy.Filesystem) bool {
-	for _, dir := range codeownersDirectories {
-		if path == fs.Join(dir, "CODEOWNERS") {
+func PathIsCodeowners(path string, fs billy.Filesystem, platform Platform) bool {
+	for _, location := range platformLocations(platform) {
+		if path == fs.Join(strings.Split(location, "/")...) {
+			return true
+		}
+	}
+	return false
+}
+
+// SlashPathIsCodeowners returns whether or not the provided slash-separated path, such as one from the
+// index or a tree, is for a valid CODEOWNERS file on the given platform, which defaults to PlatformGitHub
+func SlashPathIsCodeowners(p string, platform Platform) bool {
+	for _, location := range platformLocations(platform) {
+		if p == location {
 			return true
 		}
 	}
 	return false
 }
 
+// platformLocations returns the CODEOWNERS locations of a platform, defaulting to PlatformGitHub
+func platformLocations(platform Platform) []string {
+	if platform == "" {
+		platform = PlatformGitHub
+	}
+	return codeownersLocations[platform]
+}
+
 // Codeowners is the deserialized form of a given CODEOWNERS file
 type Codeowners []OwnerEntry
 
@@ -47,88 +88,185 @@ type OwnerEntry struct {
 	Owners     []string
 }
 
+// LineNumber returns the line of the CODEOWNERS file the entry was parsed from
+func (e OwnerEntry) LineNumber() uint64 {
+	return e.lineNumber
+}
+
 func (e OwnerEntry) String() string {
 	return fmt.Sprintf("line %d: %s\t%v", e.lineNumber, e.Pattern.String(), strings.Join(e.Owners, ", "))
 }
 
+// LoadOptions configures how a CODEOWNERS file is found
+type LoadOptions struct {
+	// Platform determines the locations searched for a CODEOWNERS file, and their precedence.
+	// Defaults to PlatformGitHub.
+	Platform Platform
+	// Path is the slash-separated path of the CODEOWNERS file to load, relative to the root of the
+	// repository. When set, no other locations are searched.
+	Path string
+}
+
+// File is a CODEOWNERS file loaded from a repository
+type File struct {
+	// Path is the slash-separated path of the loaded file, relative to the root of the repository
+	Path string
+	// Ignored lists the paths of other CODEOWNERS files that exist, but were not loaded due to precedence
+	Ignored    []string
+	Codeowners Codeowners
+	// Diagnostics describes problems found while parsing the file, including lines that were skipped
+	Diagnostics []Diagnostic
+}
+
 // LoadFromFilesystem loads and deserializes a CODEOWNERS file from the given repository, if one exists
 func LoadFromFilesystem(fs billy.Filesystem) (Codeowners, error) {
-	r, err := openCodeownersFile(fs)
+	file, err := LoadFileFromFilesystem(fs, LoadOptions{})
 	if err != nil {
 		return nil, err
 	}
+	return file.Codeowners, nil
+}
 
-	return parseCodeowners(r)
+// LoadFromTree loads and deserializes a CODEOWNERS file from the given tree of a commit, if one exists
+func LoadFromTree(tree *object.Tree) (Codeowners, error) {
+	file, err := LoadFileFromTree(tree, LoadOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return file.Codeowners, nil
 }
 
-// openCodeownersFile finds a CODEOWNERS file and returns content.
-// see: https://help.github.com/articles/about-code-owners/#codeowners-file-location
-func openCodeownersFile(fs billy.Filesystem) (io.Reader, error) {
-	for _, p := range codeownersDirectories {
-		path := fs.Join(p)
-		if _, err := fs.Stat(path); err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return nil, err
-		}
+// LoadFileFromFilesystem finds, loads and deserializes a CODEOWNERS file from the given repository
+func LoadFileFromFilesystem(fs billy.Filesystem, options LoadOptions) (*File, error) {
+	return load(filesystemSource{fs}, options)
+}
 
-		file := fs.Join(path, "CODEOWNERS")
-		_, err := fs.Stat(file)
-		if err != nil {
-			if os.IsNotExist(err) {
-				continue
-			}
-			return nil, err
-		}
+// LoadFileFromTree finds, loads and deserializes a CODEOWNERS file from the given tree of a commit
+func LoadFileFromTree(tree *object.Tree, options LoadOptions) (*File, error) {
+	return load(treeSource{tree}, options)
+}
 
-		return fs.Open(file)
+func load(src source, options LoadOptions) (*File, error) {
+	file, r, err := openCodeownersFile(src, options)
+	if err != nil {
+		return nil, err
 	}
+	defer r.Close()
 
-	return nil, fmt.Errorf("no CODEOWNERS found in the root, docs/, or .github/ directory of the repository")
+	file.Codeowners, file.Diagnostics, err = parseCodeowners(r)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
 }
 
-func parseCodeowners(r io.Reader) ([]OwnerEntry, error) {
-	var e []OwnerEntry
-	s := bufio.NewScanner(r)
-	var lineNumber uint64
-	for s.Scan() {
-		lineNumber++
-		fields := strings.Fields(s.Text())
+// openCodeownersFile finds the CODEOWNERS file that takes precedence on the platform and returns its content,
+// along with any other CODEOWNERS files that exist but are ignored.
+func openCodeownersFile(src source, options LoadOptions) (*File, io.ReadCloser, error) {
+	platform := options.Platform
+	if platform == "" {
+		platform = PlatformGitHub
+	}
+	locations, ok := codeownersLocations[platform]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported platform %q", platform)
+	}
 
-		if len(fields) == 0 { // empty
-			continue
+	file := &File{}
+	var content io.ReadCloser
+	if options.Path != "" {
+		r, err := src.open(options.Path)
+		if err != nil {
+			return nil, nil, err
 		}
+		file.Path = options.Path
+		content = r
+	}
 
-		if strings.HasPrefix(fields[0], "#") { // comment
+	for _, location := range locations {
+		if location == file.Path {
 			continue
 		}
-		pattern, err := git.CompileIgnorePattern(fields[0])
+		r, err := src.open(location)
 		if err != nil {
-			return nil, err
+			if os.IsNotExist(err) {
+				continue
+			}
+			if content != nil {
+				content.Close()
+			}
+			return nil, nil, err
 		}
-		owners := fields[1:]
 
-		e = append(e, OwnerEntry{
-			lineNumber: lineNumber,
-			Pattern:    *pattern,
-			Owners:     owners,
-		})
+		if content == nil {
+			file.Path = location
+			content = r
+		} else {
+			file.Ignored = append(file.Ignored, location)
+			r.Close()
+		}
 	}
 
-	return e, nil
+	if content == nil {
+		return nil, nil, fmt.Errorf("no CODEOWNERS found in %s of the repository", strings.Join(locations, ", "))
+	}
+	return file, content, nil
+}
+
+// Match returns the entry that decides ownership of a given path, or nil if no entry matches.
+// As with GitHub, the last matching entry in the file takes precedence.
+func (o *Codeowners) Match(path string) *OwnerEntry {
+	if o == nil {
+		return nil
+	}
+	var match *OwnerEntry
+	for i := range *o {
+		if (*o)[i].Pattern.Matches(path) {
+			match = &(*o)[i]
+		}
+	}
+	return match
 }
 
 // Owners returns the list of owners for a given path, in the event of a match
 func (o *Codeowners) Owners(path string) []string {
 	owners := []string{}
+	if entry := o.Match(path); entry != nil {
+		owners = entry.Owners
+	}
+	return owners
+}
+
+// Explanation describes how the ownership of a path was decided
+type Explanation struct {
+	Path string
+	// Matches contains every entry whose pattern matches the path, in the order they appear in the file
+	Matches []OwnerEntry
+	// Owners contains the owners of the path, taken from the last matching entry
+	Owners []string
+}
+
+// Winner returns the entry that decided the ownership of the path, or nil if no entry matches
+func (e Explanation) Winner() *OwnerEntry {
+	if len(e.Matches) == 0 {
+		return nil
+	}
+	return &e.Matches[len(e.Matches)-1]
+}
+
+// Explain returns every entry that matches the given path, and the owners they resolve to
+func (o *Codeowners) Explain(path string) Explanation {
+	explanation := Explanation{Path: path, Owners: []string{}}
 	if o == nil {
-		return owners
+		return explanation
 	}
 	for _, entry := range *o {
 		if entry.Pattern.Matches(path) {
-			owners = entry.Owners
+			explanation.Matches = append(explanation.Matches, entry)
 		}
 	}
-	return owners
+	if winner := explanation.Winner(); winner != nil {
+		explanation.Owners = winner.Owners
+	}
+	return explanation
 }
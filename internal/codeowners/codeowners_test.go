@@ -10,23 +10,38 @@ import (
 
 func TestWorktreeContainsCodeowners(t *testing.T) {
 	mockFs := memfs.New()
-	if !PathIsCodeowners(filepath.Join(".", "CODEOWNERS"), mockFs) {
+	if !PathIsCodeowners(filepath.Join(".", "CODEOWNERS"), mockFs, PlatformGitHub) {
 		t.Error("expected root to be valid for containing CODEOWNERS")
 	}
-	if !PathIsCodeowners(filepath.Join("docs", "CODEOWNERS"), mockFs) {
+	if !PathIsCodeowners(filepath.Join("docs", "CODEOWNERS"), mockFs, PlatformGitHub) {
 		t.Error("expected docs/ to be valid for containing CODEOWNERS")
 	}
-	if !PathIsCodeowners(filepath.Join(".github", "CODEOWNERS"), mockFs) {
+	if !PathIsCodeowners(filepath.Join(".github", "CODEOWNERS"), mockFs, PlatformGitHub) {
 		t.Error("expected .github/ to be valid for containing CODEOWNERS")
 	}
-	if PathIsCodeowners(filepath.Join("src", "CODEOWNERS"), mockFs) {
+	if PathIsCodeowners(filepath.Join("src", "CODEOWNERS"), mockFs, PlatformGitHub) {
 		t.Error("expected src to be invalid for containing CODEOWNERS")
 	}
-	if PathIsCodeowners(filepath.Join("github", "OWNERS"), mockFs) {
+	if PathIsCodeowners(filepath.Join("github", "OWNERS"), mockFs, PlatformGitHub) {
 		t.Error("expected github to be invalid for containing CODEOWNERS")
 	}
 }
 
+func TestCodeownersLocationsDependOnPlatform(t *testing.T) {
+	if SlashPathIsCodeowners(".gitlab/CODEOWNERS", PlatformGitHub) {
+		t.Error("expected .gitlab/CODEOWNERS to be an ordinary file on GitHub")
+	}
+	if !SlashPathIsCodeowners(".gitlab/CODEOWNERS", PlatformGitLab) {
+		t.Error("expected .gitlab/CODEOWNERS to be a CODEOWNERS file on GitLab")
+	}
+	if SlashPathIsCodeowners(".github/CODEOWNERS", PlatformGitLab) {
+		t.Error("expected .github/CODEOWNERS to be an ordinary file on GitLab")
+	}
+	if !SlashPathIsCodeowners("CODEOWNERS", "") {
+		t.Error("expected the platform to default to GitHub")
+	}
+}
+
 func TestLoadFromFilesystem(t *testing.T) {
 	mockFs := memfs.New()
 	file, _ := mockFs.Create("CODEOWNERS")
@@ -69,3 +84,143 @@ func TestOwnersFromNilReturnsEmpty(t *testing.T) {
 		t.Error("expected no owners to be returned for nil Codeowners object")
 	}
 }
+
+func TestMatchReturnsLastMatchingEntry(t *testing.T) {
+	mockFs := memfs.New()
+	file, _ := mockFs.Create("CODEOWNERS")
+	file.Write([]byte(`*		@org/everyone
+*.js	@org/team_reviewers
+*.css	@org/designers`))
+	owners, err := LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Error(err)
+	}
+	entry := owners.Match("src/dog.js")
+	if entry == nil {
+		t.Fatal("expected an entry to match 'src/dog.js'")
+	}
+	if entry.LineNumber() != 2 {
+		t.Errorf("expected line 2 to match 'src/dog.js', but line %d matched", entry.LineNumber())
+	}
+	if entry.Pattern.Source() != "*.js" {
+		t.Errorf("expected pattern source to be '*.js', but it was '%s'", entry.Pattern.Source())
+	}
+}
+
+func TestMatchFromNilReturnsNil(t *testing.T) {
+	var o *Codeowners
+	if o.Match("jeff") != nil {
+		t.Error("expected no entry to be returned for nil Codeowners object")
+	}
+}
+
+func TestLoadFileFromFilesystemPrecedence(t *testing.T) {
+	mockFs := memfs.New()
+	for _, path := range []string{"CODEOWNERS", "docs/CODEOWNERS", ".github/CODEOWNERS", ".gitlab/CODEOWNERS"} {
+		file, _ := mockFs.Create(path)
+		file.Write([]byte("* @" + path))
+		file.Close()
+	}
+
+	github, err := LoadFileFromFilesystem(mockFs, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if github.Path != ".github/CODEOWNERS" {
+		t.Errorf("expected GitHub to use .github/CODEOWNERS, but it used %s", github.Path)
+	}
+	if len(github.Ignored) != 2 || github.Ignored[0] != "CODEOWNERS" || github.Ignored[1] != "docs/CODEOWNERS" {
+		t.Errorf("expected GitHub to ignore [CODEOWNERS docs/CODEOWNERS], but it ignored %v", github.Ignored)
+	}
+	if owners := github.Codeowners.Owners("dog.js"); len(owners) != 1 || owners[0] != "@.github/CODEOWNERS" {
+		t.Errorf("expected owners to be loaded from .github/CODEOWNERS, but they were %v", owners)
+	}
+
+	gitlab, err := LoadFileFromFilesystem(mockFs, LoadOptions{Platform: PlatformGitLab})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gitlab.Path != "CODEOWNERS" {
+		t.Errorf("expected GitLab to use CODEOWNERS, but it used %s", gitlab.Path)
+	}
+	if len(gitlab.Ignored) != 2 || gitlab.Ignored[0] != "docs/CODEOWNERS" || gitlab.Ignored[1] != ".gitlab/CODEOWNERS" {
+		t.Errorf("expected GitLab to ignore [docs/CODEOWNERS .gitlab/CODEOWNERS], but it ignored %v", gitlab.Ignored)
+	}
+}
+
+func TestLoadFileFromFilesystemExplicitPath(t *testing.T) {
+	mockFs := memfs.New()
+	for _, path := range []string{"CODEOWNERS", "config/OWNERS"} {
+		file, _ := mockFs.Create(path)
+		file.Write([]byte("* @" + path))
+		file.Close()
+	}
+
+	file, err := LoadFileFromFilesystem(mockFs, LoadOptions{Path: "config/OWNERS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Path != "config/OWNERS" {
+		t.Errorf("expected config/OWNERS to be used, but %s was", file.Path)
+	}
+	if len(file.Ignored) != 1 || file.Ignored[0] != "CODEOWNERS" {
+		t.Errorf("expected [CODEOWNERS] to be ignored, but %v were", file.Ignored)
+	}
+
+	_, err = LoadFileFromFilesystem(mockFs, LoadOptions{Path: "missing/CODEOWNERS"})
+	if err == nil {
+		t.Error("expected a missing explicit CODEOWNERS to fail loading")
+	}
+}
+
+func TestLoadFileFromFilesystemUnsupportedPlatform(t *testing.T) {
+	mockFs := memfs.New()
+	file, _ := mockFs.Create("CODEOWNERS")
+	file.Write([]byte("* @org/everyone"))
+	file.Close()
+
+	_, err := LoadFileFromFilesystem(mockFs, LoadOptions{Platform: Platform("sourcehut")})
+	if err == nil {
+		t.Error("expected an unsupported platform to fail loading")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	mockFs := memfs.New()
+	file, _ := mockFs.Create("CODEOWNERS")
+	file.Write([]byte(`*		@org/everyone
+*.css	@org/designers
+src/	@org/infra
+*.js	@org/frontend`))
+	owners, err := LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explanation := owners.Explain("src/app.js")
+	if len(explanation.Matches) != 3 {
+		t.Fatalf("expected 3 matching entries, but there were %d", len(explanation.Matches))
+	}
+	for i, line := range []uint64{1, 3, 4} {
+		if explanation.Matches[i].LineNumber() != line {
+			t.Errorf("expected match %d to be line %d, but it was line %d", i, line, explanation.Matches[i].LineNumber())
+		}
+	}
+	if winner := explanation.Winner(); winner == nil || winner.LineNumber() != 4 {
+		t.Errorf("expected line 4 to win, but %v did", winner)
+	}
+	if len(explanation.Owners) != 1 || explanation.Owners[0] != "@org/frontend" {
+		t.Errorf("expected owners to be [@org/frontend], but they were %v", explanation.Owners)
+	}
+}
+
+func TestExplainWithoutMatches(t *testing.T) {
+	var o Codeowners
+	explanation := o.Explain("jeff")
+	if explanation.Winner() != nil {
+		t.Error("expected no winner to be returned for nil Codeowners object")
+	}
+	if len(explanation.Owners) != 0 {
+		t.Error("expected no owners to be returned for nil Codeowners object")
+	}
+}
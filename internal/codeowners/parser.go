@@ -0,0 +1,175 @@
+package codeowners
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/aaronsky/codeowners-coverage/internal/git"
+)
+
+// maxLineLength is the longest line parseCodeowners accepts
+const maxLineLength = 1024 * 1024
+
+var (
+	handlePattern = regexp.MustCompile(`^@[\w.-]+(/[\w.-]+)*$`)
+	emailPattern  = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+)
+
+// Severity indicates how serious a Diagnostic is
+type Severity string
+
+const (
+	// SeverityError marks a problem that caused a line to be skipped
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem that may not behave as intended, but did not cause a line to be skipped
+	SeverityWarning Severity = "warning"
+)
+
+// DiagnosticCode identifies the kind of problem a Diagnostic describes
+type DiagnosticCode string
+
+const (
+	// CodeInvalidPattern marks a pattern that cannot be parsed
+	CodeInvalidPattern DiagnosticCode = "invalid-pattern"
+	// CodeUnsupportedPattern marks a pattern that parses, but is not supported by GitHub
+	CodeUnsupportedPattern DiagnosticCode = "unsupported-pattern"
+	// CodeInvalidOwner marks an owner that is not a valid handle or email address
+	CodeInvalidOwner DiagnosticCode = "invalid-owner"
+)
+
+// Diagnostic describes a problem found while parsing a CODEOWNERS file
+type Diagnostic struct {
+	Line     uint64
+	Column   int
+	Severity Severity
+	Code     DiagnosticCode
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", d.Line, d.Column, d.Severity, d.Message)
+}
+
+// token is a whitespace-delimited run of characters on a line, with its 1-based column
+type token struct {
+	text   string
+	column int
+}
+
+// parseCodeowners reads the entries of a CODEOWNERS file. Rather than failing on the first problem, lines
+// that cannot be parsed are skipped and described by the returned diagnostics. An error is only returned
+// if the file cannot be read.
+// see: https://help.github.com/articles/about-code-owners/#codeowners-syntax
+func parseCodeowners(r io.Reader) (Codeowners, []Diagnostic, error) {
+	var e []OwnerEntry
+	var diagnostics []Diagnostic
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxLineLength)
+	var lineNumber uint64
+	for s.Scan() {
+		lineNumber++
+		line := s.Text()
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		line = strings.TrimRight(line, "\r")
+
+		tokens, err := tokenizeLine(line)
+		if err != nil {
+			diagnostics = append(diagnostics, Diagnostic{Line: lineNumber, Column: len([]rune(line)), Severity: SeverityError, Code: CodeInvalidPattern, Message: err.Error()})
+			continue
+		}
+		if len(tokens) == 0 { // empty or comment
+			continue
+		}
+
+		entry, entryDiagnostics := parseEntry(lineNumber, tokens)
+		diagnostics = append(diagnostics, entryDiagnostics...)
+		if entry != nil {
+			e = append(e, *entry)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return e, diagnostics, nil
+}
+
+// tokenizeLine splits a line into its pattern and owners. Whitespace preceded by a backslash is part of the
+// pattern, and a "#" at the start of a token begins a comment that runs to the end of the line.
+func tokenizeLine(line string) ([]token, error) {
+	var tokens []token
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' {
+			i++
+			continue
+		}
+		if runes[i] == '#' {
+			break
+		}
+
+		start := i
+		for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' {
+			if runes[i] == '\\' {
+				i++
+				if i == len(runes) {
+					return nil, fmt.Errorf("line ends with an unfinished escape sequence")
+				}
+			}
+			i++
+		}
+		tokens = append(tokens, token{text: string(runes[start:i]), column: start + 1})
+	}
+	return tokens, nil
+}
+
+// parseEntry compiles the pattern and validates the owners of a tokenized line. If the line is invalid,
+// no entry is returned.
+func parseEntry(lineNumber uint64, tokens []token) (*OwnerEntry, []Diagnostic) {
+	var diagnostics []Diagnostic
+	diagnose := func(t token, severity Severity, code DiagnosticCode, format string, args ...interface{}) {
+		diagnostics = append(diagnostics, Diagnostic{
+			Line:     lineNumber,
+			Column:   t.column,
+			Severity: severity,
+			Code:     code,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	patternToken := tokens[0]
+	pattern, err := git.CompileIgnorePattern(patternToken.text)
+	if err != nil {
+		diagnose(patternToken, SeverityError, CodeInvalidPattern, "%s", err)
+		return nil, diagnostics
+	}
+	if strings.HasPrefix(patternToken.text, "!") {
+		diagnose(patternToken, SeverityError, CodeUnsupportedPattern, "negated patterns are not supported by GitHub, so the line is ignored")
+		return nil, diagnostics
+	}
+
+	owners := []string{}
+	valid := true
+	for _, ownerToken := range tokens[1:] {
+		if !handlePattern.MatchString(ownerToken.text) && !emailPattern.MatchString(ownerToken.text) {
+			diagnose(ownerToken, SeverityError, CodeInvalidOwner, "%q is not a valid owner, which must be a @username, @org/team-name or email address", ownerToken.text)
+			valid = false
+			continue
+		}
+		owners = append(owners, ownerToken.text)
+	}
+	if !valid {
+		return nil, diagnostics
+	}
+
+	return &OwnerEntry{
+		lineNumber: lineNumber,
+		Pattern:    *pattern,
+		Owners:     owners,
+	}, diagnostics
+}
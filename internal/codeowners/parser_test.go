@@ -0,0 +1,123 @@
+package codeowners
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseInlineComments(t *testing.T) {
+	entries, diagnostics, err := parseCodeowners(strings.NewReader("*.go @org/team # backend\n# a full line comment\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, but there were %v", diagnostics)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, but there were %d", len(entries))
+	}
+	if len(entries[0].Owners) != 1 || entries[0].Owners[0] != "@org/team" {
+		t.Errorf("expected owners to be [@org/team], but they were %v", entries[0].Owners)
+	}
+}
+
+func TestParseEscapedWhitespace(t *testing.T) {
+	entries, diagnostics, err := parseCodeowners(strings.NewReader(`docs/My\ Documents/ @org/writers`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, but there were %v", diagnostics)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, but there were %d", len(entries))
+	}
+	if !entries[0].Pattern.Matches("docs/My Documents/notes.txt") {
+		t.Error("expected pattern with escaped space to match path with space")
+	}
+	if len(entries[0].Owners) != 1 {
+		t.Errorf("expected 1 owner, but there were %v", entries[0].Owners)
+	}
+}
+
+func TestParseByteOrderMarkAndCRLF(t *testing.T) {
+	entries, diagnostics, err := parseCodeowners(strings.NewReader("\uFEFF*.js @org/frontend\r\n*.go @org/backend\r\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, but there were %v", diagnostics)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, but there were %d", len(entries))
+	}
+	if !entries[0].Pattern.Matches("index.js") {
+		t.Error("expected byte order mark to be stripped from the first pattern")
+	}
+	if entries[1].Owners[0] != "@org/backend" {
+		t.Errorf("expected carriage return to be stripped from owners, but owner was %q", entries[1].Owners[0])
+	}
+}
+
+func TestParseUnowned(t *testing.T) {
+	entries, diagnostics, err := parseCodeowners(strings.NewReader("* @org/everyone\n/generated/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diagnostics) != 0 {
+		t.Errorf("expected no diagnostics, but there were %v", diagnostics)
+	}
+	codeowners := Codeowners(entries)
+	if owners := codeowners.Owners("generated/api.go"); len(owners) != 0 {
+		t.Errorf("expected generated files to be explicitly unowned, but they were owned by %v", owners)
+	}
+	if owners := codeowners.Owners("main.go"); len(owners) != 1 {
+		t.Errorf("expected main.go to be owned, but it was owned by %v", owners)
+	}
+}
+
+func TestParseDiagnostics(t *testing.T) {
+	entries, diagnostics, err := parseCodeowners(strings.NewReader(`*.js @org/frontend
+src/[a-z @org/backend
+*.md docs@example.com not-an-owner
+!*.css @org/design
+*.go @org/backend
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, but there were %d", len(entries))
+	}
+	if len(diagnostics) != 3 {
+		t.Fatalf("expected 3 diagnostics, but there were %v", diagnostics)
+	}
+
+	pattern := diagnostics[0]
+	if pattern.Line != 2 || pattern.Column != 1 || pattern.Severity != SeverityError || pattern.Code != CodeInvalidPattern {
+		t.Errorf("expected an error at 2:1 for the unterminated bracket, but got %s", pattern)
+	}
+	owner := diagnostics[1]
+	if owner.Line != 3 || owner.Column != 23 || owner.Severity != SeverityError || owner.Code != CodeInvalidOwner {
+		t.Errorf("expected an error at 3:23 for the invalid owner, but got %s", owner)
+	}
+	negation := diagnostics[2]
+	if negation.Line != 4 || negation.Column != 1 || negation.Severity != SeverityError || negation.Code != CodeUnsupportedPattern {
+		t.Errorf("expected an error at 4:1 for the negated pattern, but got %s", negation)
+	}
+	if entries[1].LineNumber() != 5 {
+		t.Errorf("expected parsing to continue after errors, but the last entry was from line %d", entries[1].LineNumber())
+	}
+}
+
+func TestParseIgnoresNegatedPatterns(t *testing.T) {
+	entries, _, err := parseCodeowners(strings.NewReader(`!vendor/ @org/vendor
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	codeowners := Codeowners(entries)
+	if owners := codeowners.Owners("src/main.go"); len(owners) != 0 {
+		t.Errorf("expected a negated pattern to own nothing, but src/main.go was owned by %v", owners)
+	}
+}
@@ -0,0 +1,41 @@
+package codeowners
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+// source is a location CODEOWNERS files can be read from
+type source interface {
+	// open opens the file at the given slash-separated path. If the file does not exist,
+	// the returned error satisfies os.IsNotExist.
+	open(path string) (io.ReadCloser, error)
+}
+
+// filesystemSource reads files from a worktree or other billy.Filesystem
+type filesystemSource struct {
+	fs billy.Filesystem
+}
+
+func (s filesystemSource) open(path string) (io.ReadCloser, error) {
+	return s.fs.Open(filepath.FromSlash(path))
+}
+
+// treeSource reads files from the tree of a commit, without requiring a worktree
+type treeSource struct {
+	tree *object.Tree
+}
+
+func (s treeSource) open(path string) (io.ReadCloser, error) {
+	file, err := s.tree.File(path)
+	if err == object.ErrFileNotFound || err == object.ErrDirectoryNotFound {
+		return nil, os.ErrNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	return file.Reader()
+}
@@ -3,6 +3,7 @@ package git
 
 import (
 	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
 )
 
 // Open opens a repository on-disk at the given path. All operations from here-on are performed using the on-disk filesystem.
@@ -21,3 +22,25 @@ func CleanWorktree(worktree *git.Worktree) error {
 		Dir: true,
 	})
 }
+
+// TrackedFiles returns the slash-separated paths of the files tracked in the index of the given repository,
+// excluding submodules and files that the given worktree status reports as deleted. The worktree is not modified.
+func TrackedFiles(repository *git.Repository, status Status) ([]string, error) {
+	index, err := repository.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range index.Entries {
+		if entry.Mode == filemode.Submodule {
+			continue
+		}
+		// Status.File would insert an entry for an unknown path, so look it up directly
+		if fileStatus, ok := status[entry.Name]; ok && fileStatus.Worktree == git.Deleted {
+			continue
+		}
+		paths = append(paths, entry.Name)
+	}
+	return paths, nil
+}
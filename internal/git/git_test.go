@@ -1 +1,48 @@
 package git
+
+import (
+	"testing"
+
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func TestTrackedFiles(t *testing.T) {
+	fs := memfs.New()
+	repository, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"README.md", "src/app.js", "src/deleted.js", "untracked.js"} {
+		file, _ := fs.Create(path)
+		file.Write([]byte(path))
+		file.Close()
+	}
+	for _, path := range []string{"README.md", "src/app.js", "src/deleted.js"} {
+		if _, err := worktree.Add(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fs.Remove("src/deleted.js")
+
+	status, err := worktree.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := TrackedFiles(repository, status)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != "README.md" || paths[1] != "src/app.js" {
+		t.Errorf("expected tracked files to be [README.md src/app.js], but they were %v", paths)
+	}
+	if _, err := fs.Stat("untracked.js"); err != nil {
+		t.Error("expected untracked file to be left in the worktree")
+	}
+}
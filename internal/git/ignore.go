@@ -3,31 +3,21 @@ package git
 import (
 	"fmt"
 	"os"
-	"regexp"
 	"strings"
 )
 
-var (
-	leadingHashOrBangPattern         = regexp.MustCompile(`^(\#|\!)`)
-	prependSlashForFileGlobPattern   = regexp.MustCompile(`([^\/+])/.*\*\.`)
-	escapingDotSlashPattern          = regexp.MustCompile(`\.`)
-	innerDoubleAsteriskPattern       = regexp.MustCompile(`/\*\*/`)
-	leadingDoubleAsteriskPattern     = regexp.MustCompile(`\*\*/`)
-	trailingDoubleAsteriskPattern    = regexp.MustCompile(`/\*\*`)
-	veryEscapedSingleAsteriskPattern = regexp.MustCompile(`\\\*`)
-	escapedSingleAsteriskPattern     = regexp.MustCompile(`\*`)
-)
-
-// IgnorePattern aliases string to add some additional documentation
+// IgnorePattern is a compiled gitignore-style pattern, as used in .gitignore and CODEOWNERS files
 type IgnorePattern struct {
 	lineNumber uint64
-	pattern    *regexp.Regexp
-	negate     bool
-}
-
-// newIgnorePattern creates a new Pattern object behind a pointer
-func newIgnorePattern(regex *regexp.Regexp, negate bool) *IgnorePattern {
-	return &IgnorePattern{pattern: regex, negate: negate}
+	source     string
+	// glob is the wildmatch pattern matched against full slash-separated paths
+	glob string
+	// dirOnly is set when the pattern had a trailing slash, so it only matches directories
+	dirOnly bool
+	// childrenOnly is set when the pattern ends with a "/*" segment, which GitHub matches against the
+	// direct children of a directory only, rather than everything beneath them
+	childrenOnly bool
+	negate       bool
 }
 
 func (p *IgnorePattern) String() string {
@@ -35,19 +25,46 @@ func (p *IgnorePattern) String() string {
 	if p.negate {
 		negatedString += "not "
 	}
-	return fmt.Sprintf("%s%s", negatedString, p.pattern)
+	dirString := ""
+	if p.dirOnly {
+		dirString = "/"
+	}
+	return fmt.Sprintf("%s%s%s", negatedString, p.glob, dirString)
+}
+
+// Source returns the pattern as it was originally written, before compilation
+func (p *IgnorePattern) Source() string {
+	return p.source
 }
 
 // Matches returns whether or not a given path matches the Codeowners path pattern
 func (p *IgnorePattern) Matches(path string) bool {
 	path = strings.Replace(path, string(os.PathSeparator), "/", -1)
+	path = strings.TrimPrefix(path, "/")
 	if p.negate {
-		return !p.pattern.MatchString(path)
+		return !p.matches(path)
 	}
-	return p.pattern.MatchString(path)
+	return p.matches(path)
+}
+
+// matches returns whether the pattern matches the path itself or, as a directory, any of its parents
+func (p *IgnorePattern) matches(path string) bool {
+	if !p.dirOnly && wildmatch(p.glob, path) {
+		return true
+	}
+	if p.childrenOnly {
+		return false
+	}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && wildmatch(p.glob, path[:i]) {
+			return true
+		}
+	}
+	return false
 }
 
 // CompileIgnorePattern takes a given ignore pattern and attempts to create a Pattern object from it
+// see: https://git-scm.com/docs/gitignore#_pattern_format
 func CompileIgnorePattern(pattern string) (*IgnorePattern, error) {
 	// Trim OS-specific carriage returns.
 	pattern = strings.TrimRight(pattern, "\r")
@@ -58,96 +75,91 @@ func CompileIgnorePattern(pattern string) (*IgnorePattern, error) {
 	}
 
 	// Trailing spaces are ignored unless they are quoted with backslash ("\").
-	pattern = strings.Trim(pattern, " ")
+	pattern = trimTrailingSpaces(pattern)
 
 	// A blank line matches no files, so it can serve as a separator for readability.
 	if pattern == "" {
 		return nil, fmt.Errorf("intentionally not compiling empty pattern")
 	}
+	source := pattern
 
 	// An optional prefix "!" which negates the pattern; any matching file excluded by a previous
-	// pattern will become included again. It is not possible to re-include a file if a parent
-	// directory of that file is excluded. Git doesn’t list excluded directories for performance
-	// reasons, so any patterns on contained files have no effect, no matter where they are defined.
-	// Put a backslash ("\") in front of the first "!" for patterns that begin with a literal "!",
-	// for example, "\!important!.txt".
+	// pattern will become included again. Put a backslash ("\") in front of the first "!" for
+	// patterns that begin with a literal "!", for example, "\!important!.txt". A backslash in
+	// front of the first hash is handled the same way, and both are left for wildmatch to unescape.
 	negatePattern := false
 	if pattern[0] == '!' {
 		negatePattern = true
 		pattern = pattern[1:]
 	}
 
-	// Trailing spaces are ignored unless they are quoted with backslash ("\").
-	// Put a backslash ("\") in front of the first hash for patterns that begin with a hash.
-	if leadingHashOrBangPattern.MatchString(pattern) {
-		pattern = pattern[1:]
-	}
-
-	// The slash / is used as the directory separator. Separators may occur at the beginning,
-	// middle or end of the .gitignore search pattern.
-	if prependSlashForFileGlobPattern.MatchString(pattern) && pattern[0] != '/' {
-		pattern = "/" + pattern
-	}
-
-	// Handle escaping the "." char
-	pattern = escapingDotSlashPattern.ReplaceAllString(pattern, `\.`)
-
-	magicStar := "#$~"
-	pattern = handleConsecutiveAsterisks(pattern, magicStar)
-
-	// Handle escaping the "?" char
-	pattern = strings.Replace(pattern, "?", `\?`, -1)
-	// An asterisk "*" matches anything except a slash. The character "?" matches any one character
-	// except "/". The range notation, e.g. [a-zA-Z], can be used to match one of the characters in
-	// a range. See fnmatch(3) and the FNM_PATHNAME flag for a more detailed description.
-	pattern = strings.Replace(pattern, magicStar, "*", -1)
-
-	var expr = ""
 	// If there is a separator at the end of the pattern then the pattern will only match
 	// directories, otherwise the pattern can match both files and directories.
-	if strings.HasSuffix(pattern, "/") {
-		expr = pattern + "(|.*)$"
-	} else {
-		expr = pattern + "(|/.*)$"
+	dirOnly := false
+	if strings.HasSuffix(pattern, "/") && !strings.HasSuffix(pattern, `\/`) {
+		dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
 	}
+
 	// If there is a separator at the beginning or middle (or both) of the pattern, then the
 	// pattern is relative to the directory level of the particular .gitignore file itself.
 	// Otherwise the pattern may also match at any level below the .gitignore level.
-	if strings.HasPrefix(expr, "/") {
-		expr = "^(|/)" + expr[1:]
-	} else {
-		expr = "^(|.*/)" + expr
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	if pattern == "" {
+		return nil, fmt.Errorf("pattern %q does not match any path", source)
+	}
+	if err := validateGlob(pattern); err != nil {
+		return nil, fmt.Errorf("pattern %q is invalid: %s", source, err)
 	}
 
-	regex, err := regexp.Compile(expr)
-	if err != nil {
-		return nil, err
+	childrenOnly := anchored && !dirOnly && (pattern == "*" || strings.HasSuffix(pattern, "/*"))
+	if !anchored {
+		pattern = "**/" + pattern
 	}
 
-	return newIgnorePattern(regex, negatePattern), nil
+	return &IgnorePattern{
+		source:       source,
+		glob:         pattern,
+		dirOnly:      dirOnly,
+		childrenOnly: childrenOnly,
+		negate:       negatePattern,
+	}, nil
 }
 
-func handleConsecutiveAsterisks(pattern, magicStar string) string {
-	// Two consecutive asterisks ("**") in patterns matched against full pathname may have special meaning
+// trimTrailingSpaces removes trailing spaces from a pattern, unless they are escaped with a backslash
+func trimTrailingSpaces(pattern string) string {
+	end := len(pattern)
+	for end > 0 && pattern[end-1] == ' ' {
+		backslashes := 0
+		for i := end - 2; i >= 0 && pattern[i] == '\\'; i-- {
+			backslashes++
+		}
+		if backslashes%2 == 1 {
+			break
+		}
+		end--
+	}
+	return pattern[:end]
+}
 
-	// Handle "/**/" usage
-	if strings.HasPrefix(pattern, "/**/") {
-		pattern = pattern[1:]
+// validateGlob reports bracket expressions and escapes that can never match, which git silently ignores
+func validateGlob(glob string) error {
+	pattern := []rune(glob)
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+			if i == len(pattern) {
+				return fmt.Errorf("trailing backslash")
+			}
+		case '[':
+			end := classEnd(pattern, i)
+			if end < 0 {
+				return fmt.Errorf("unterminated bracket expression")
+			}
+			i = end
+		}
 	}
-	// A slash followed by two consecutive asterisks then a slash matches zero or more directories.
-	// For example, "a/**\/b" matches "a/b", "a/x/b", "a/x/y/b" and so on.
-	pattern = innerDoubleAsteriskPattern.ReplaceAllString(pattern, `(/|/.+/)`)
-	// A leading "**" followed by a slash means match in all directories. For example, "**\/foo"
-	// matches file or directory "foo" anywhere, the same as pattern "foo". "**\/foo/bar" matches
-	// file or directory "bar" anywhere that is directly under directory "foo".
-	pattern = leadingDoubleAsteriskPattern.ReplaceAllString(pattern, `(|.`+magicStar+`/)`)
-	// A trailing "/**" matches everything inside. For example, "abc/**" matches all files inside directory "abc", relative to the location of the .gitignore file, with infinite depth.
-	pattern = trailingDoubleAsteriskPattern.ReplaceAllString(pattern, `(|/.`+magicStar+`)`)
-	// Other consecutive asterisks are considered regular asterisks and will match according to the previous rules.
-
-	// Handle escaping the "*" char
-	pattern = veryEscapedSingleAsteriskPattern.ReplaceAllString(pattern, `\`+magicStar)
-	pattern = escapedSingleAsteriskPattern.ReplaceAllString(pattern, `([^/]*)`)
-
-	return pattern
+	return nil
 }
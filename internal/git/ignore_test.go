@@ -96,8 +96,13 @@ func TestTrailingGlobPattern(t *testing.T) {
 	if !pattern.Matches("docs/dog.js") {
 		t.Error("expected string to match pattern")
 	}
-	if !pattern.Matches("rock/docs/dog.js") {
-		t.Error("expected string to match pattern")
+	// a slash in the middle of the pattern anchors it to the root
+	if pattern.Matches("rock/docs/dog.js") {
+		t.Error("expected string not to match pattern")
+	}
+	// a trailing "/*" only matches direct children
+	if pattern.Matches("docs/rock/dog.js") {
+		t.Error("expected string not to match pattern")
 	}
 }
 
@@ -142,3 +147,98 @@ func TestNegationPattern(t *testing.T) {
 		t.Error("expected string to match pattern")
 	}
 }
+
+func TestCompileIgnorePatternErrors(t *testing.T) {
+	for _, pattern := range []string{"!", "/", "src/[a-z", `trailing\`} {
+		compiled, err := CompileIgnorePattern(pattern)
+		if err == nil {
+			t.Errorf("expected %q to not compile, but it compiled to %s", pattern, compiled)
+		}
+	}
+}
+
+func TestIgnorePatternMatches(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		matches bool
+	}{
+		// unanchored patterns match at any level
+		{"*.js", "index.js", true},
+		{"*.js", "src/lib/index.js", true},
+		{"*.js", "index.jsx", false},
+		{"README.md", "docs/README.md", true},
+		{"apps/", "apps/main.go", true},
+		{"apps/", "nested/apps/main.go", true},
+		{"apps/", "apps", false},
+		// anchored patterns match relative to the root
+		{"/README.md", "README.md", true},
+		{"/README.md", "docs/README.md", false},
+		{"/docs/", "docs/a/b/c.md", true},
+		{"/docs/", "src/docs/c.md", false},
+		{"src/lib", "src/lib/index.js", true},
+		{"src/lib", "vendor/src/lib/index.js", false},
+		{"/*", "README.md", true},
+		{"/*", "src/index.js", false},
+		// "?" matches a single character other than "/"
+		{"file?.txt", "file1.txt", true},
+		{"file?.txt", "file10.txt", false},
+		{"file?.txt", "file.txt", false},
+		{"a?b", "a/b", false},
+		{"?.md", "docs/a.md", true},
+		// bracket expressions
+		{"[a-c].go", "b.go", true},
+		{"[a-c].go", "d.go", false},
+		{"[!a-c].go", "d.go", true},
+		{"[^a-c].go", "a.go", false},
+		{"v[0-9][0-9].txt", "v42.txt", true},
+		{"v[0-9][0-9].txt", "v4a.txt", false},
+		{"[]].txt", "].txt", true},
+		{"[!]].txt", "a.txt", true},
+		{"[a-].txt", "-.txt", true},
+		{"[[:digit:]].txt", "7.txt", true},
+		{"[[:digit:]].txt", "x.txt", false},
+		{"[[:upper:][:digit:]]x", "Ax", true},
+		{"a[/]b", "a/b", false},
+		// escapes
+		{`\#hash.txt`, "#hash.txt", true},
+		{`\!bang.txt`, "!bang.txt", true},
+		{`\*.txt`, "*.txt", true},
+		{`\*.txt`, "a.txt", false},
+		{`\?.txt`, "?.txt", true},
+		{`\?.txt`, "a.txt", false},
+		{`\[a].txt`, "[a].txt", true},
+		{`with\ space.txt`, "with space.txt", true},
+		{`trailing\ `, "trailing ", true},
+		{"trailing   ", "trailing", true},
+		// double asterisks
+		{"**/logs", "logs/debug.log", true},
+		{"**/logs", "build/logs/debug.log", true},
+		{"**/logs/debug.log", "build/logs/debug.log", true},
+		{"logs/**", "logs/a/b/debug.log", true},
+		{"logs/**", "build/logs/debug.log", false},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/xb", false},
+		{"a/**b", "a/xb", true},
+		{"a/**b", "a/x/b", false},
+		{"/**/*.md", "a/b/c.md", true},
+		// single asterisks never cross directories
+		{"src/*.js", "src/index.js", true},
+		{"src/*.js", "src/lib/index.js", false},
+		{"src/*/index.js", "src/lib/index.js", true},
+		{"src/*/index.js", "src/lib/deep/index.js", false},
+		{"*", "a/b/c", true},
+	}
+
+	for _, c := range cases {
+		pattern, err := CompileIgnorePattern(c.pattern)
+		if err != nil {
+			t.Errorf("expected %q to compile, but got %s", c.pattern, err)
+			continue
+		}
+		if pattern.Matches(c.path) != c.matches {
+			t.Errorf("expected %q (compiled to %s) matching %q to be %t", c.pattern, pattern, c.path, c.matches)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package git
+
+import (
+	"io"
+	"sort"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing"
+	"gopkg.in/src-d/go-git.v4/plumbing/filemode"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/utils/merkletrie"
+)
+
+// ResolveCommit resolves a revision, such as a branch, tag or SHA, to a commit in the given repository
+func ResolveCommit(repository *git.Repository, revision string) (*object.Commit, error) {
+	hash, err := repository.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, err
+	}
+	return repository.CommitObject(*hash)
+}
+
+// TreeFiles returns the slash-separated paths of every file in the given tree, recursively, excluding
+// submodules. No blobs are read, so this works on bare repositories and is independent of any worktree.
+func TreeFiles(tree *object.Tree) ([]string, error) {
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
+	var paths []string
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if entry.Mode == filemode.Dir || entry.Mode == filemode.Submodule {
+			continue
+		}
+		paths = append(paths, name)
+	}
+	return paths, nil
+}
+
+// ChangeAction describes how a file changed between two trees
+type ChangeAction string
+
+const (
+	// ChangeAdded is a file that only exists in the newer tree
+	ChangeAdded ChangeAction = "added"
+	// ChangeModified is a file that exists in both trees with different content or mode
+	ChangeModified ChangeAction = "modified"
+	// ChangeRenamed is a file that was moved to a new path with identical content
+	ChangeRenamed ChangeAction = "renamed"
+	// ChangeDeleted is a file that only exists in the older tree
+	ChangeDeleted ChangeAction = "deleted"
+)
+
+// Change is a file that differs between two trees
+type Change struct {
+	Action ChangeAction
+	// Path is the slash-separated path of the file, or the path it was deleted from
+	Path string
+	// PreviousPath is the path a renamed file was moved from
+	PreviousPath string
+}
+
+// DiffTrees returns the files that differ between two trees, sorted by path. Files that were deleted
+// and added elsewhere with identical content are reported as a single rename.
+func DiffTrees(from, to *object.Tree) ([]Change, error) {
+	treeChanges, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	deletedByHash := map[plumbing.Hash][]int{}
+	var added []object.ChangeEntry
+	for _, treeChange := range treeChanges {
+		action, err := treeChange.Action()
+		if err != nil {
+			return nil, err
+		}
+		switch action {
+		case merkletrie.Insert:
+			if treeChange.To.TreeEntry.Mode != filemode.Submodule {
+				added = append(added, treeChange.To)
+			}
+		case merkletrie.Delete:
+			if treeChange.From.TreeEntry.Mode != filemode.Submodule {
+				hash := treeChange.From.TreeEntry.Hash
+				deletedByHash[hash] = append(deletedByHash[hash], len(changes))
+				changes = append(changes, Change{Action: ChangeDeleted, Path: treeChange.From.Name})
+			}
+		case merkletrie.Modify:
+			if treeChange.To.TreeEntry.Mode != filemode.Submodule {
+				changes = append(changes, Change{Action: ChangeModified, Path: treeChange.To.Name})
+			}
+		}
+	}
+
+	for _, entry := range added {
+		candidates := deletedByHash[entry.TreeEntry.Hash]
+		if len(candidates) == 0 {
+			changes = append(changes, Change{Action: ChangeAdded, Path: entry.Name})
+			continue
+		}
+		deleted := &changes[candidates[0]]
+		deletedByHash[entry.TreeEntry.Hash] = candidates[1:]
+		deleted.Action = ChangeRenamed
+		deleted.PreviousPath = deleted.Path
+		deleted.Path = entry.Name
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes, nil
+}
@@ -0,0 +1,118 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"gopkg.in/src-d/go-billy.v4"
+	"gopkg.in/src-d/go-billy.v4/memfs"
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
+	"gopkg.in/src-d/go-git.v4/storage/memory"
+)
+
+func TestTreeFiles(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitChanges(t, repository, fs, map[string]string{"README.md": "readme", "src/app.js": "app"}, nil)
+
+	tree := resolveTree(t, repository, "HEAD")
+	paths, err := TreeFiles(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != "README.md" || paths[1] != "src/app.js" {
+		t.Errorf("expected tree files to be [README.md src/app.js], but they were %v", paths)
+	}
+}
+
+func TestDiffTrees(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitChanges(t, repository, fs, map[string]string{
+		"README.md":     "readme",
+		"src/app.js":    "app",
+		"src/old.js":    "moving",
+		"src/delete.js": "deleting",
+	}, nil)
+	base := resolveTree(t, repository, "HEAD")
+	commitChanges(t, repository, fs, map[string]string{
+		"src/app.js": "app, modified",
+		"src/new.js": "moving",
+		"added.go":   "added",
+	}, []string{"src/old.js", "src/delete.js"})
+	head := resolveTree(t, repository, "HEAD")
+
+	changes, err := DiffTrees(base, head)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Change{
+		{Action: ChangeAdded, Path: "added.go"},
+		{Action: ChangeModified, Path: "src/app.js"},
+		{Action: ChangeDeleted, Path: "src/delete.js"},
+		{Action: ChangeRenamed, Path: "src/new.js", PreviousPath: "src/old.js"},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, but there were %v", len(expected), changes)
+	}
+	for i := range expected {
+		if changes[i] != expected[i] {
+			t.Errorf("expected change %d to be %+v, but it was %+v", i, expected[i], changes[i])
+		}
+	}
+}
+
+func TestResolveUnknownCommit(t *testing.T) {
+	repository, fs := setupRepository(t)
+	commitChanges(t, repository, fs, map[string]string{"README.md": "readme"}, nil)
+
+	if _, err := ResolveCommit(repository, "does-not-exist"); err == nil {
+		t.Error("expected unknown revision to fail")
+	}
+}
+
+func setupRepository(t *testing.T) (*git.Repository, billy.Filesystem) {
+	fs := memfs.New()
+	repository, err := git.Init(memory.NewStorage(), fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return repository, fs
+}
+
+func commitChanges(t *testing.T, repository *git.Repository, fs billy.Filesystem, files map[string]string, removed []string) {
+	worktree, err := repository.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range files {
+		file, _ := fs.Create(path)
+		file.Write([]byte(content))
+		file.Close()
+		if _, err := worktree.Add(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range removed {
+		if _, err := worktree.Remove(path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = worktree.Commit("commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "jeff", Email: "jeff@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func resolveTree(t *testing.T, repository *git.Repository, revision string) *object.Tree {
+	commit, err := ResolveCommit(repository, revision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
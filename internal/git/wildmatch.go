@@ -0,0 +1,278 @@
+package git
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wildmatch results, mirroring git's wildmatch.c. The abort results let a failed match
+// stop backtracking early, since no later starting point can succeed either.
+const (
+	wildmatchMatch = iota
+	wildmatchNoMatch
+	wildmatchAbortAll
+	wildmatchAbortToDoubleStar
+)
+
+// wildmatch reports whether a slash-separated path matches a glob, following the semantics of
+// git's wildmatch with the WM_PATHNAME flag:
+//
+//   - "?" matches any single character except "/"
+//   - "*" matches any run of characters except "/"
+//   - "**" matches across directories when it is a whole segment, as in "**/a", "a/**/b" or "a/**"
+//   - "[...]" matches one character from a set, which may contain ranges ("a-z"), POSIX classes
+//     ("[:digit:]"), and may be negated with a leading "!" or "^"
+//   - "\" escapes the following character, so that it is matched literally
+func wildmatch(glob, path string) bool {
+	return dowild([]rune(glob), []rune(path)) == wildmatchMatch
+}
+
+func dowild(pattern, text []rune) int {
+	p, t := 0, 0
+	for ; p < len(pattern); p, t = p+1, t+1 {
+		pCh := pattern[p]
+		if t == len(text) && pCh != '*' {
+			return wildmatchAbortAll
+		}
+		var tCh rune
+		if t < len(text) {
+			tCh = text[t]
+		}
+
+		switch pCh {
+		case '\\':
+			// Literal match with the following character
+			p++
+			if p == len(pattern) || tCh != pattern[p] {
+				return wildmatchNoMatch
+			}
+		case '?':
+			// Match anything but "/"
+			if tCh == '/' {
+				return wildmatchNoMatch
+			}
+		case '*':
+			matchSlash := false
+			p++
+			if p < len(pattern) && pattern[p] == '*' {
+				prev := p - 2
+				for p < len(pattern) && pattern[p] == '*' {
+					p++
+				}
+				if (prev < 0 || pattern[prev] == '/') &&
+					(p == len(pattern) || pattern[p] == '/' || (pattern[p] == '\\' && p+1 < len(pattern) && pattern[p+1] == '/')) {
+					// Assuming we already match "foo/" and are at "**/", try matching nothing,
+					// so that "foo/**/bar" matches both "foo/bar" and "foo/a/bar".
+					if p < len(pattern) && pattern[p] == '/' && dowild(pattern[p+1:], text[t:]) == wildmatchMatch {
+						return wildmatchMatch
+					}
+					matchSlash = true
+				}
+			}
+
+			if p == len(pattern) {
+				// A trailing "**" matches everything. A trailing "*" matches only if there are no more slashes.
+				if !matchSlash && containsRune(text[t:], '/') {
+					return wildmatchNoMatch
+				}
+				return wildmatchMatch
+			} else if !matchSlash && pattern[p] == '/' {
+				// A single asterisk followed by a slash matches the rest of the current directory name
+				slash := indexRune(text[t:], '/')
+				if slash < 0 {
+					return wildmatchNoMatch
+				}
+				t += slash
+				// the slash is consumed by the loop
+				continue
+			}
+
+			for {
+				if t == len(text) {
+					break
+				}
+				// Advance faster when the asterisk is followed by a literal, since the text before the
+				// literal must belong to the asterisk. Without matchSlash, do not look past a slash.
+				if !isGlobSpecial(pattern[p]) {
+					for t < len(text) && (matchSlash || text[t] != '/') && text[t] != pattern[p] {
+						t++
+					}
+					if t == len(text) || text[t] != pattern[p] {
+						return wildmatchNoMatch
+					}
+				}
+				matched := dowild(pattern[p:], text[t:])
+				if matched != wildmatchNoMatch {
+					if !matchSlash || matched != wildmatchAbortToDoubleStar {
+						return matched
+					}
+				} else if !matchSlash && text[t] == '/' {
+					return wildmatchAbortToDoubleStar
+				}
+				t++
+			}
+			return wildmatchAbortAll
+		case '[':
+			end := classEnd(pattern, p)
+			if end < 0 {
+				return wildmatchAbortAll
+			}
+			if tCh == '/' || !classMatches(pattern[p+1:end], tCh) {
+				return wildmatchNoMatch
+			}
+			p = end
+		default:
+			if tCh != pCh {
+				return wildmatchNoMatch
+			}
+		}
+	}
+
+	if t < len(text) {
+		return wildmatchNoMatch
+	}
+	return wildmatchMatch
+}
+
+// classEnd returns the index of the "]" closing the bracket expression opened at start, or -1 if it is unterminated.
+// A "]" immediately after the opening bracket (or its negation) is a literal member of the set.
+func classEnd(pattern []rune, start int) int {
+	i := start + 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		i++
+	}
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '[':
+			if end := posixClassEnd(pattern, i); end >= 0 {
+				i = end
+			}
+		case ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// classMatches reports whether a character is a member of the contents of a bracket expression
+func classMatches(class []rune, ch rune) bool {
+	negated := false
+	if len(class) > 0 && (class[0] == '!' || class[0] == '^') {
+		negated = true
+		class = class[1:]
+	}
+
+	matched := false
+	var prev rune
+	hasPrev := false
+	for i := 0; i < len(class); i++ {
+		c := class[i]
+		switch {
+		case c == '\\' && i+1 < len(class):
+			i++
+			c = class[i]
+			if ch == c {
+				matched = true
+			}
+		case c == '-' && hasPrev && i+1 < len(class):
+			i++
+			upper := class[i]
+			if upper == '\\' && i+1 < len(class) {
+				i++
+				upper = class[i]
+			}
+			if prev <= ch && ch <= upper {
+				matched = true
+			}
+			// a range cannot be the start of another range
+			hasPrev = false
+			continue
+		case c == '[' && posixClassEnd(class, i) >= 0:
+			end := posixClassEnd(class, i)
+			if posixClassMatches(string(class[i+2:end-1]), ch) {
+				matched = true
+			}
+			i = end
+			hasPrev = false
+			continue
+		default:
+			if ch == c {
+				matched = true
+			}
+		}
+		prev = c
+		hasPrev = true
+	}
+
+	return matched != negated
+}
+
+// posixClassMatches reports whether a character belongs to a named POSIX character class, such as "digit"
+func posixClassMatches(name string, ch rune) bool {
+	switch name {
+	case "alnum":
+		return unicode.IsLetter(ch) || unicode.IsDigit(ch)
+	case "alpha":
+		return unicode.IsLetter(ch)
+	case "blank":
+		return ch == ' ' || ch == '\t'
+	case "cntrl":
+		return unicode.IsControl(ch)
+	case "digit":
+		return unicode.IsDigit(ch)
+	case "graph":
+		return unicode.IsGraphic(ch) && !unicode.IsSpace(ch)
+	case "lower":
+		return unicode.IsLower(ch)
+	case "print":
+		return unicode.IsPrint(ch)
+	case "punct":
+		return unicode.IsPunct(ch) || unicode.IsSymbol(ch)
+	case "space":
+		return unicode.IsSpace(ch)
+	case "upper":
+		return unicode.IsUpper(ch)
+	case "xdigit":
+		return strings.ContainsRune("0123456789abcdefABCDEF", ch)
+	default:
+		return false
+	}
+}
+
+// isGlobSpecial reports whether a character has a special meaning in a glob
+func isGlobSpecial(ch rune) bool {
+	return ch == '*' || ch == '?' || ch == '[' || ch == '\\'
+}
+
+// containsRune reports whether r is within runes
+func containsRune(runes []rune, r rune) bool {
+	return indexRune(runes, r) >= 0
+}
+
+// indexRune returns the index of the first instance of r in runes, or -1 if r is not present
+func indexRune(runes []rune, r rune) int {
+	for i, c := range runes {
+		if c == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// posixClassEnd returns the index of the "]" closing a POSIX character class such as "[:digit:]" opened at start,
+// or -1 if there is none. Like git, only the first "]" is considered, and it must be preceded by ":".
+func posixClassEnd(pattern []rune, start int) int {
+	if start+1 >= len(pattern) || pattern[start] != '[' || pattern[start+1] != ':' {
+		return -1
+	}
+	end := indexRune(pattern[start+2:], ']')
+	if end < 1 || pattern[start+2+end-1] != ':' {
+		return -1
+	}
+	return start + 2 + end
+}
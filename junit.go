@@ -0,0 +1,164 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// junitTestSuites is the root element of a JUnit XML report
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+	SystemOut  string          `xml:"system-out,omitempty"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// toJUnit renders the report as JUnit XML, with a test case for each file that fails when the file has no owners.
+// The CODEOWNERS patterns that were evaluated are listed once, in the suite's output.
+func (r *Report) toJUnit() (string, error) {
+	suite := r.newJUnitTestSuite("files")
+	var text strings.Builder
+	if len(r.Rules) == 0 {
+		text.WriteString("No CODEOWNERS patterns were evaluated.\n")
+	} else {
+		text.WriteString("Evaluated CODEOWNERS patterns:\n")
+		for _, rule := range r.Rules {
+			fmt.Fprintf(&text, "line %d: %s\n", rule.LineNumber, rule.Pattern)
+		}
+	}
+	suite.SystemOut = text.String()
+	for _, file := range r.Files {
+		testCase := junitTestCase{ClassName: path.Dir(file.Path), Name: file.Path}
+		if len(file.Owners) == 0 {
+			testCase.Failure = newFileJUnitFailure(file)
+		}
+		suite.add(testCase)
+	}
+	return marshalJUnit(suite)
+}
+
+// toJUnitDirectories renders the report as JUnit XML, with a test case for each directory that fails when the
+// directory contains files with no owners
+func (r *Report) toJUnitDirectories() (string, error) {
+	suite := r.newJUnitTestSuite("directories")
+	if r.Directories != nil {
+		uncoveredFiles := r.uncoveredFilesByDirectory()
+		r.Directories.walk(func(dir *DirectoryCoverage) {
+			testCase := junitTestCase{ClassName: path.Dir(dir.Path), Name: dir.Path}
+			if dir.CoveredFilesCount < dir.TotalFilesCount {
+				testCase.Failure = newDirectoryJUnitFailure(dir, uncoveredFiles[dir.Path])
+			}
+			suite.add(testCase)
+		})
+	}
+	return marshalJUnit(suite)
+}
+
+// newJUnitTestSuite creates an empty test suite, with the repository and commit of the report as properties
+func (r *Report) newJUnitTestSuite(name string) *junitTestSuite {
+	return &junitTestSuite{
+		Name: "codeowners-coverage " + name,
+		Properties: []junitProperty{
+			{Name: "remote_url", Value: r.RemoteURL},
+			{Name: "sha", Value: r.SHA},
+			{Name: "codeowners_path", Value: r.CodeownersPath},
+		},
+	}
+}
+
+// add appends a test case to the suite, and counts it
+func (s *junitTestSuite) add(testCase junitTestCase) {
+	s.Tests++
+	if testCase.Failure != nil {
+		s.Failures++
+	}
+	s.Cases = append(s.Cases, testCase)
+}
+
+// newFileJUnitFailure describes why a file has no owners, naming the CODEOWNERS rule that matched it, if any
+func newFileJUnitFailure(file FileCoverage) *junitFailure {
+	if file.Rule == nil {
+		return &junitFailure{
+			Message: "no CODEOWNERS rule matches " + file.Path,
+			Type:    "unowned",
+			Text:    "No CODEOWNERS pattern matches this file.\n",
+		}
+	}
+	return &junitFailure{
+		Message: fmt.Sprintf("%s is matched by CODEOWNERS line %d (%s), which assigns no owners", file.Path, file.Rule.LineNumber, file.Rule.Pattern),
+		Type:    "unowned",
+		Text:    fmt.Sprintf("Matching CODEOWNERS pattern:\nline %d: %s\n", file.Rule.LineNumber, file.Rule.Pattern),
+	}
+}
+
+// uncoveredFilesByDirectory groups the files with no owners under every directory that contains them, including "."
+func (r *Report) uncoveredFilesByDirectory() map[string][]string {
+	directories := map[string][]string{}
+	for _, file := range r.Files {
+		if len(file.Owners) > 0 {
+			continue
+		}
+		for dir := path.Dir(file.Path); dir != "."; dir = path.Dir(dir) {
+			directories[dir] = append(directories[dir], file.Path)
+		}
+		directories["."] = append(directories["."], file.Path)
+	}
+	return directories
+}
+
+// newDirectoryJUnitFailure describes a directory with files that have no owners, listing those files
+func newDirectoryJUnitFailure(dir *DirectoryCoverage, uncoveredFiles []string) *junitFailure {
+	uncoveredFilesCount := dir.TotalFilesCount - dir.CoveredFilesCount
+	message := fmt.Sprintf("%d of %d files in %s have no owners", uncoveredFilesCount, dir.TotalFilesCount, dir.Path)
+
+	var text strings.Builder
+	text.WriteString("Files with no owners:\n")
+	for _, file := range uncoveredFiles {
+		text.WriteString(file + "\n")
+	}
+	return &junitFailure{Message: message, Type: "unowned", Text: text.String()}
+}
+
+// marshalJUnit wraps a test suite in a document and encodes it as indented XML
+func marshalJUnit(suite *junitTestSuite) (string, error) {
+	suites := junitTestSuites{
+		Name:     "codeowners-coverage",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{*suite},
+	}
+	bytes, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(bytes), nil
+}
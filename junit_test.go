@@ -0,0 +1,99 @@
+package coverage
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestToFormatJUnit(t *testing.T) {
+	report := newJUnitReport()
+	output, err := report.ToFormat(ReportFormatJUnit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatal(err)
+	}
+	if suites.Tests != 3 || suites.Failures != 2 {
+		t.Errorf("expected 3 tests with 2 failures, but there were %d tests with %d failures", suites.Tests, suites.Failures)
+	}
+
+	cases := suites.Suites[0].Cases
+	if cases[0].Failure != nil {
+		t.Errorf("expected %s to pass", cases[0].Name)
+	}
+	if failure := cases[1].Failure; failure == nil || failure.Message != "legacy/old.c is matched by CODEOWNERS line 2 (legacy/), which assigns no owners" {
+		t.Errorf("expected %s to fail for its rule with no owners, but got %+v", cases[1].Name, failure)
+	} else if failure.Text != "Matching CODEOWNERS pattern:\nline 2: legacy/\n" {
+		t.Errorf("expected the failure to list only the matching pattern, but it was %q", failure.Text)
+	}
+	if failure := cases[2].Failure; failure == nil || failure.Message != "no CODEOWNERS rule matches notes.txt" {
+		t.Errorf("expected %s to fail for matching no rule, but got %+v", cases[2].Name, failure)
+	} else if strings.Contains(failure.Text, "*.go") {
+		t.Errorf("expected the failure not to repeat the evaluated patterns, but it was %q", failure.Text)
+	}
+	if systemOut := suites.Suites[0].SystemOut; !strings.Contains(systemOut, "line 1: *.go\nline 2: legacy/\n") {
+		t.Errorf("expected the suite to list the evaluated patterns, but its output was %q", systemOut)
+	}
+	if cases[2].ClassName != "." {
+		t.Errorf("expected a file at the root to have the class name \".\", but it was %q", cases[2].ClassName)
+	}
+}
+
+func TestToFormatJUnitDirectories(t *testing.T) {
+	report := newJUnitReport()
+	output, err := report.ToFormat(ReportFormatJUnitDirectories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suites junitTestSuites
+	if err := xml.Unmarshal([]byte(output), &suites); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		".":      "2 of 3 files in . have no owners",
+		"legacy": "1 of 1 files in legacy have no owners",
+		"src":    "",
+	}
+	expectedText := map[string]string{
+		".":      "Files with no owners:\nlegacy/old.c\nnotes.txt\n",
+		"legacy": "Files with no owners:\nlegacy/old.c\n",
+	}
+	cases := suites.Suites[0].Cases
+	if len(cases) != len(expected) {
+		t.Fatalf("expected %d test cases, but there were %d", len(expected), len(cases))
+	}
+	for _, testCase := range cases {
+		message := ""
+		if testCase.Failure != nil {
+			message = testCase.Failure.Message
+		}
+		if message != expected[testCase.Name] {
+			t.Errorf("expected directory %s to have failure %q, but it was %q", testCase.Name, expected[testCase.Name], message)
+		}
+		if testCase.Failure != nil && testCase.Failure.Text != expectedText[testCase.Name] {
+			t.Errorf("expected directory %s to list %q, but it listed %q", testCase.Name, expectedText[testCase.Name], testCase.Failure.Text)
+		}
+	}
+}
+
+func newJUnitReport() Report {
+	files := []FileCoverage{
+		{Path: "src/main.go", Owners: []string{"@org/go"}, Rule: &RuleMatch{Pattern: "*.go", LineNumber: 1}},
+		{Path: "legacy/old.c", Owners: []string{}, Rule: &RuleMatch{Pattern: "legacy/", LineNumber: 2}},
+		{Path: "notes.txt", Owners: []string{}},
+	}
+	return Report{
+		CoveredFilesCount: 1,
+		TotalFilesCount:   3,
+		Files:             files,
+		Directories:       newDirectoryCoverage(files, 0),
+		Rules: []RuleCoverage{
+			{Pattern: "*.go", LineNumber: 1, Owners: []string{"@org/go"}},
+			{Pattern: "legacy/", LineNumber: 2, Owners: []string{}},
+		},
+	}
+}
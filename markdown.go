@@ -0,0 +1,79 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// toMarkdown renders the report as GitHub-flavored Markdown, with tables summarizing coverage overall, by owner
+// and by directory, and a collapsible list of uncovered files. The output depends only on the report's contents,
+// so it can be compared between runs.
+func (r *Report) toMarkdown() string {
+	var b strings.Builder
+
+	b.WriteString("## CODEOWNERS coverage\n\n")
+	b.WriteString("| Repository | Commit | Covered files | Total files | Coverage |\n")
+	b.WriteString("| --- | --- | ---: | ---: | ---: |\n")
+	fmt.Fprintf(&b, "| %s | %s | %d | %d | %s |\n",
+		markdownCode(r.RemoteURL), markdownCode(r.SHA), r.CoveredFilesCount, r.TotalFilesCount, formatPercent(r.CoverageRatio))
+
+	if len(r.Owners) > 0 {
+		b.WriteString("\n### Owners\n\n")
+		b.WriteString("| Owner | Files | Share of files | Rules |\n")
+		b.WriteString("| --- | ---: | ---: | ---: |\n")
+		for _, owner := range r.Owners {
+			fmt.Fprintf(&b, "| %s | %d | %s | %d |\n",
+				markdownCode(owner.Owner), owner.FilesCount, formatPercent(owner.FilesRatio), owner.RulesCount)
+		}
+	}
+
+	if r.Directories != nil {
+		b.WriteString("\n### Directories\n\n")
+		b.WriteString("| Directory | Covered files | Total files | Coverage | Dominant owner |\n")
+		b.WriteString("| --- | ---: | ---: | ---: | --- |\n")
+		r.Directories.walk(func(dir *DirectoryCoverage) {
+			fmt.Fprintf(&b, "| %s | %d | %d | %s | %s |\n",
+				markdownCode(dir.Path), dir.CoveredFilesCount, dir.TotalFilesCount, formatPercent(dir.CoverageRatio), markdownCode(dir.DominantOwner))
+		})
+	}
+
+	if len(r.UncoveredFiles) > 0 {
+		uncoveredFiles := append([]string{}, r.UncoveredFiles...)
+		sort.Strings(uncoveredFiles)
+
+		fmt.Fprintf(&b, "\n<details>\n<summary>Uncovered files (%d)</summary>\n\n", len(uncoveredFiles))
+		for _, path := range uncoveredFiles {
+			fmt.Fprintf(&b, "- %s\n", markdownCode(path))
+		}
+		b.WriteString("\n</details>\n")
+	}
+
+	return b.String()
+}
+
+// walk calls fn for the directory and each of its descendants, depth-first in path order
+func (d *DirectoryCoverage) walk(fn func(*DirectoryCoverage)) {
+	fn(d)
+	for _, child := range d.Children {
+		child.walk(fn)
+	}
+}
+
+// formatPercent formats a ratio between 0 and 1 as a percentage with one decimal place
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
+
+// markdownCode formats a value as inline code that is safe to place in a table cell, or returns an empty string
+// for an empty value
+func markdownCode(value string) string {
+	if value == "" {
+		return ""
+	}
+	value = strings.Replace(value, "|", `\|`, -1)
+	if strings.Contains(value, "`") {
+		return "`` " + value + " ``"
+	}
+	return "`" + value + "`"
+}
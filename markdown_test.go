@@ -0,0 +1,77 @@
+package coverage
+
+import "testing"
+
+func TestToFormatMarkdown(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "README.md", Owners: []string{"@org/docs"}},
+		{Path: "legacy/old|name.c", Owners: []string{}},
+		{Path: "services/api/main.go", Owners: []string{"@org/api"}},
+	}
+	report := Report{
+		RemoteURL:         "git@github.com:org/repo.git",
+		SHA:               "abc123",
+		CoveredFilesCount: 2,
+		TotalFilesCount:   3,
+		CoverageRatio:     2.0 / 3.0,
+		Files:             files,
+		UncoveredFiles:    []string{"legacy/old|name.c"},
+		Owners: []OwnerCoverage{
+			{Owner: "@org/api", FilesCount: 1, FilesRatio: 1.0 / 3.0, RulesCount: 1},
+			{Owner: "@org/docs", FilesCount: 1, FilesRatio: 1.0 / 3.0, RulesCount: 1},
+		},
+		Directories: newDirectoryCoverage(files, 0),
+	}
+
+	markdown, err := report.ToFormat(ReportFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## CODEOWNERS coverage\n" +
+		"\n" +
+		"| Repository | Commit | Covered files | Total files | Coverage |\n" +
+		"| --- | --- | ---: | ---: | ---: |\n" +
+		"| `git@github.com:org/repo.git` | `abc123` | 2 | 3 | 66.7% |\n" +
+		"\n" +
+		"### Owners\n" +
+		"\n" +
+		"| Owner | Files | Share of files | Rules |\n" +
+		"| --- | ---: | ---: | ---: |\n" +
+		"| `@org/api` | 1 | 33.3% | 1 |\n" +
+		"| `@org/docs` | 1 | 33.3% | 1 |\n" +
+		"\n" +
+		"### Directories\n" +
+		"\n" +
+		"| Directory | Covered files | Total files | Coverage | Dominant owner |\n" +
+		"| --- | ---: | ---: | ---: | --- |\n" +
+		"| `.` | 2 | 3 | 66.7% | `@org/api` |\n" +
+		"| `legacy` | 0 | 1 | 0.0% |  |\n" +
+		"| `services` | 1 | 1 | 100.0% | `@org/api` |\n" +
+		"| `services/api` | 1 | 1 | 100.0% | `@org/api` |\n" +
+		"\n" +
+		"<details>\n" +
+		"<summary>Uncovered files (1)</summary>\n" +
+		"\n" +
+		"- `legacy/old\\|name.c`\n" +
+		"\n" +
+		"</details>\n"
+	if markdown != expected {
+		t.Errorf("expected Markdown\n%s\nbut got\n%s", expected, markdown)
+	}
+}
+
+func TestToFormatMarkdownEmpty(t *testing.T) {
+	report := Report{}
+	markdown, err := report.ToFormat(ReportFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "## CODEOWNERS coverage\n" +
+		"\n" +
+		"| Repository | Commit | Covered files | Total files | Coverage |\n" +
+		"| --- | --- | ---: | ---: | ---: |\n" +
+		"|  |  | 0 | 0 | 0.0% |\n"
+	if markdown != expected {
+		t.Errorf("expected Markdown\n%s\nbut got\n%s", expected, markdown)
+	}
+}
@@ -0,0 +1,121 @@
+package coverage
+
+import (
+	"path"
+	"sort"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+)
+
+// maxOwnerTopDirectories is the number of directories listed for each owner in a Report
+const maxOwnerTopDirectories = 5
+
+// OwnerCoverage contains information on the files a single owner is responsible for
+type OwnerCoverage struct {
+	Owner          string                `json:"owner"`
+	FilesCount     int                   `json:"files_count"`
+	FilesRatio     float64               `json:"files_ratio"`
+	RulesCount     int                   `json:"rules_count"`
+	TopDirectories []OwnedDirectoryCount `json:"top_directories"`
+}
+
+// OwnedDirectoryCount contains the number of files an owner is responsible for in a single directory
+type OwnedDirectoryCount struct {
+	Path       string `json:"path"`
+	FilesCount int    `json:"files_count"`
+}
+
+// newOwnersCoverage aggregates the resolved owners of each file into a breakdown per owner,
+// sorted by the number of files owned in descending order.
+func newOwnersCoverage(files []FileCoverage, owners codeowners.Codeowners) []OwnerCoverage {
+	tally := newOwnersTally(owners)
+	for _, file := range files {
+		tally.add(file)
+	}
+	return tally.breakdown()
+}
+
+// ownersTally counts the files each owner is responsible for as they are resolved, so that the files need
+// not be kept
+type ownersTally struct {
+	byOwner            map[string]*OwnerCoverage
+	directoriesByOwner map[string]map[string]int
+	filesCount         int
+}
+
+// newOwnersTally creates a tally with no files, which counts the rules that name each owner
+func newOwnersTally(owners codeowners.Codeowners) *ownersTally {
+	tally := &ownersTally{byOwner: map[string]*OwnerCoverage{}, directoriesByOwner: map[string]map[string]int{}}
+	for _, entry := range owners {
+		for _, owner := range uniqueStrings(entry.Owners) {
+			tally.get(owner).RulesCount++
+		}
+	}
+	return tally
+}
+
+// get returns the coverage of the owner, creating it if needed
+func (t *ownersTally) get(owner string) *OwnerCoverage {
+	if _, ok := t.byOwner[owner]; !ok {
+		t.byOwner[owner] = &OwnerCoverage{Owner: owner, TopDirectories: []OwnedDirectoryCount{}}
+		t.directoriesByOwner[owner] = map[string]int{}
+	}
+	return t.byOwner[owner]
+}
+
+// add counts the file towards each of its owners
+func (t *ownersTally) add(file FileCoverage) {
+	t.filesCount++
+	dir := path.Dir(file.Path)
+	for _, owner := range uniqueStrings(file.Owners) {
+		t.get(owner).FilesCount++
+		t.directoriesByOwner[owner][dir]++
+	}
+}
+
+// breakdown returns the coverage of each owner, sorted by the number of files owned in descending order
+func (t *ownersTally) breakdown() []OwnerCoverage {
+	breakdown := make([]OwnerCoverage, 0, len(t.byOwner))
+	for owner, coverage := range t.byOwner {
+		if t.filesCount > 0 {
+			coverage.FilesRatio = float64(coverage.FilesCount) / float64(t.filesCount)
+		}
+		for dir, count := range t.directoriesByOwner[owner] {
+			coverage.TopDirectories = append(coverage.TopDirectories, OwnedDirectoryCount{Path: dir, FilesCount: count})
+		}
+		sort.Slice(coverage.TopDirectories, func(i, j int) bool {
+			a, b := coverage.TopDirectories[i], coverage.TopDirectories[j]
+			if a.FilesCount != b.FilesCount {
+				return a.FilesCount > b.FilesCount
+			}
+			return a.Path < b.Path
+		})
+		if len(coverage.TopDirectories) > maxOwnerTopDirectories {
+			coverage.TopDirectories = coverage.TopDirectories[:maxOwnerTopDirectories]
+		}
+		breakdown = append(breakdown, *coverage)
+	}
+	sort.Slice(breakdown, func(i, j int) bool {
+		a, b := breakdown[i], breakdown[j]
+		if a.FilesCount != b.FilesCount {
+			return a.FilesCount > b.FilesCount
+		}
+		return a.Owner < b.Owner
+	})
+
+	return breakdown
+}
+
+// uniqueStrings returns the given strings with duplicates removed, preserving order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
@@ -0,0 +1,56 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+)
+
+func TestSetCoverageOwners(t *testing.T) {
+	report := Report{}
+
+	mockStatus, mockFs, _ := setupPopulatedFilesystem()
+	owners, err := codeowners.LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = report.setCoverage(mockStatus, mockFs, owners, Options{})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(report.Owners) != 1 {
+		t.Fatalf("expected 1 owner, but there were %d", len(report.Owners))
+	}
+	owner := report.Owners[0]
+	if owner.Owner != "@org/team_reviewers" {
+		t.Errorf("expected owner to be @org/team_reviewers, but it was %s", owner.Owner)
+	}
+	if owner.FilesCount != 2 {
+		t.Errorf("expected owner to have 2 files, but it had %d", owner.FilesCount)
+	}
+	if owner.FilesRatio != 0.4 {
+		t.Errorf("expected owner files ratio to be 0.4, but it was %f", owner.FilesRatio)
+	}
+	if owner.RulesCount != 1 {
+		t.Errorf("expected owner to be mentioned in 1 rule, but it was mentioned in %d", owner.RulesCount)
+	}
+	if len(owner.TopDirectories) != 2 || owner.TopDirectories[0].Path != "." || owner.TopDirectories[1].Path != "src" {
+		t.Errorf("expected owner top directories to be [. src], but they were %+v", owner.TopDirectories)
+	}
+}
+
+func TestNewOwnersCoverageOrdering(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "a/one.go", Owners: []string{"@b", "@a"}},
+		{Path: "a/two.go", Owners: []string{"@b"}},
+		{Path: "three.go", Owners: []string{"@c"}},
+	}
+	breakdown := newOwnersCoverage(files, nil)
+	if len(breakdown) != 3 {
+		t.Fatalf("expected 3 owners, but there were %d", len(breakdown))
+	}
+	if breakdown[0].Owner != "@b" || breakdown[1].Owner != "@a" || breakdown[2].Owner != "@c" {
+		t.Errorf("expected owners to be ordered [@b @a @c], but they were [%s %s %s]", breakdown[0].Owner, breakdown[1].Owner, breakdown[2].Owner)
+	}
+}
@@ -0,0 +1,54 @@
+package coverage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// toPrometheus renders the report in the Prometheus text exposition format, such as for the node_exporter
+// textfile collector. Every sample is labelled with the repository's remote URL and commit.
+func (r *Report) toPrometheus() string {
+	var b strings.Builder
+	labels := []string{"remote_url", r.RemoteURL, "sha", r.SHA}
+
+	writePrometheusMetric(&b, "codeowners_coverage_covered_files", "Number of files with at least one owner.")
+	writePrometheusSample(&b, "codeowners_coverage_covered_files", labels, float64(r.CoveredFilesCount))
+	writePrometheusMetric(&b, "codeowners_coverage_total_files", "Number of files in the repository.")
+	writePrometheusSample(&b, "codeowners_coverage_total_files", labels, float64(r.TotalFilesCount))
+	writePrometheusMetric(&b, "codeowners_coverage_ratio", "Ratio of files with at least one owner.")
+	writePrometheusSample(&b, "codeowners_coverage_ratio", labels, r.CoverageRatio)
+
+	if len(r.Owners) > 0 {
+		writePrometheusMetric(&b, "codeowners_coverage_owner_files", "Number of files owned by each owner.")
+		for _, owner := range r.Owners {
+			writePrometheusSample(&b, "codeowners_coverage_owner_files", append(labels, "owner", owner.Owner), float64(owner.FilesCount))
+		}
+	}
+
+	if r.Directories != nil && len(r.Directories.Children) > 0 {
+		writePrometheusMetric(&b, "codeowners_coverage_directory_ratio", "Ratio of files with at least one owner in each top-level directory.")
+		for _, dir := range r.Directories.Children {
+			writePrometheusSample(&b, "codeowners_coverage_directory_ratio", append(labels, "directory", dir.Path), dir.CoverageRatio)
+		}
+	}
+
+	return b.String()
+}
+
+// writePrometheusMetric writes the HELP and TYPE lines that precede the samples of a gauge
+func writePrometheusMetric(b *strings.Builder, name, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// writePrometheusSample writes a sample of a metric, with labels given as alternating names and values
+func writePrometheusSample(b *strings.Builder, name string, labels []string, value float64) {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labels[i], prometheusLabelEscaper.Replace(labels[i+1])))
+	}
+	fmt.Fprintf(b, "%s{%s} %s\n", name, strings.Join(pairs, ","), strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+// prometheusLabelEscaper escapes the characters that are not allowed verbatim in label values
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
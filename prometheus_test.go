@@ -0,0 +1,44 @@
+package coverage
+
+import "testing"
+
+func TestToFormatPrometheus(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "src/main.go", Owners: []string{"@org/go"}},
+		{Path: "docs/index.md", Owners: []string{}},
+	}
+	report := Report{
+		RemoteURL:         `https://example.com/"repo".git`,
+		SHA:               "abc123",
+		CoveredFilesCount: 1,
+		TotalFilesCount:   2,
+		CoverageRatio:     0.5,
+		Owners:            []OwnerCoverage{{Owner: "@org/go", FilesCount: 1}},
+		Directories:       newDirectoryCoverage(files, 0),
+	}
+
+	metrics, err := report.ToFormat(ReportFormatPrometheus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	labels := `remote_url="https://example.com/\"repo\".git",sha="abc123"`
+	expected := "# HELP codeowners_coverage_covered_files Number of files with at least one owner.\n" +
+		"# TYPE codeowners_coverage_covered_files gauge\n" +
+		"codeowners_coverage_covered_files{" + labels + "} 1\n" +
+		"# HELP codeowners_coverage_total_files Number of files in the repository.\n" +
+		"# TYPE codeowners_coverage_total_files gauge\n" +
+		"codeowners_coverage_total_files{" + labels + "} 2\n" +
+		"# HELP codeowners_coverage_ratio Ratio of files with at least one owner.\n" +
+		"# TYPE codeowners_coverage_ratio gauge\n" +
+		"codeowners_coverage_ratio{" + labels + "} 0.5\n" +
+		"# HELP codeowners_coverage_owner_files Number of files owned by each owner.\n" +
+		"# TYPE codeowners_coverage_owner_files gauge\n" +
+		"codeowners_coverage_owner_files{" + labels + `,owner="@org/go"} 1` + "\n" +
+		"# HELP codeowners_coverage_directory_ratio Ratio of files with at least one owner in each top-level directory.\n" +
+		"# TYPE codeowners_coverage_directory_ratio gauge\n" +
+		"codeowners_coverage_directory_ratio{" + labels + `,directory="docs"} 0` + "\n" +
+		"codeowners_coverage_directory_ratio{" + labels + `,directory="src"} 1` + "\n"
+	if metrics != expected {
+		t.Errorf("expected metrics\n%s\nbut got\n%s", expected, metrics)
+	}
+}
@@ -0,0 +1,44 @@
+package coverage
+
+import (
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+)
+
+// RuleCoverage describes how a single CODEOWNERS entry applied to the files in a repository
+type RuleCoverage struct {
+	Pattern              string   `json:"pattern"`
+	LineNumber           uint64   `json:"line_number"`
+	Owners               []string `json:"owners"`
+	MatchedFilesCount    int      `json:"matched_files_count"`
+	DecidedFilesCount    int      `json:"decided_files_count"`
+	ShadowedByLineNumber uint64   `json:"shadowed_by_line_number,omitempty"`
+}
+
+// Dead returns whether or not the rule matched no files at all
+func (r RuleCoverage) Dead() bool {
+	return r.MatchedFilesCount == 0
+}
+
+// Shadowed returns whether or not the rule matched files, but later rules decided the ownership of all of them
+func (r RuleCoverage) Shadowed() bool {
+	return r.MatchedFilesCount > 0 && r.DecidedFilesCount == 0
+}
+
+// newRulesCoverage converts the statistics of a codeowners.Analysis into RuleCoverage objects
+func newRulesCoverage(analysis *codeowners.Analysis) []RuleCoverage {
+	stats := analysis.Rules()
+	rules := make([]RuleCoverage, len(stats))
+	for i, stat := range stats {
+		rules[i] = RuleCoverage{
+			Pattern:           stat.Entry.Pattern.Source(),
+			LineNumber:        stat.Entry.LineNumber(),
+			Owners:            stat.Entry.Owners,
+			MatchedFilesCount: stat.MatchedCount,
+			DecidedFilesCount: stat.DecidedCount,
+		}
+		if stat.ShadowedBy != nil {
+			rules[i].ShadowedByLineNumber = stat.ShadowedBy.LineNumber()
+		}
+	}
+	return rules
+}
@@ -0,0 +1,35 @@
+package coverage
+
+import (
+	"testing"
+
+	"github.com/aaronsky/codeowners-coverage/internal/codeowners"
+)
+
+func TestSetCoverageRules(t *testing.T) {
+	report := Report{}
+
+	mockStatus, mockFs, _ := setupPopulatedFilesystem()
+	owners, err := codeowners.LoadFromFilesystem(mockFs)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = report.setCoverage(mockStatus, mockFs, owners, Options{})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(report.Rules) != 1 {
+		t.Fatalf("expected 1 rule, but there were %d", len(report.Rules))
+	}
+	rule := report.Rules[0]
+	if rule.Pattern != "*.js" || rule.LineNumber != 1 {
+		t.Errorf("expected rule to be line 1 (*.js), but it was line %d (%s)", rule.LineNumber, rule.Pattern)
+	}
+	if rule.MatchedFilesCount != 2 || rule.DecidedFilesCount != 2 {
+		t.Errorf("expected rule to match and decide 2 files, but it matched %d and decided %d", rule.MatchedFilesCount, rule.DecidedFilesCount)
+	}
+	if rule.Dead() || rule.Shadowed() {
+		t.Error("expected rule to be neither dead nor shadowed")
+	}
+}
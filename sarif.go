@@ -0,0 +1,193 @@
+package coverage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// scpLikeRemotePattern matches remotes in Git's scp-like syntax, such as git@github.com:org/repo.git, which has
+// no scheme and a colon before the first slash
+var scpLikeRemotePattern = regexp.MustCompile(`^(?:([^@/]+)@)?([^:/]+):(.*)$`)
+
+// sarifRules are the kinds of results reported in SARIF output, which are defined once in the tool's driver
+var sarifRules = []sarifRule{
+	{ID: "unowned-file", ShortDescription: sarifMessage{Text: "File has no owners in CODEOWNERS"}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "dead-rule", ShortDescription: sarifMessage{Text: "CODEOWNERS rule matches no files"}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "shadowed-rule", ShortDescription: sarifMessage{Text: "CODEOWNERS rule is overridden by later rules for every file it matches"}, DefaultConfiguration: sarifConfiguration{Level: "warning"}},
+	{ID: "invalid-pattern", ShortDescription: sarifMessage{Text: "CODEOWNERS pattern cannot be parsed"}, DefaultConfiguration: sarifConfiguration{Level: "error"}},
+	{ID: "unsupported-pattern", ShortDescription: sarifMessage{Text: "CODEOWNERS pattern is not supported by GitHub, and is ignored"}, DefaultConfiguration: sarifConfiguration{Level: "error"}},
+	{ID: "invalid-owner", ShortDescription: sarifMessage{Text: "CODEOWNERS owner is not a valid handle or email address"}, DefaultConfiguration: sarifConfiguration{Level: "error"}},
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool                     sarifTool             `json:"tool"`
+	VersionControlProvenance []sarifVersionControl `json:"versionControlProvenance,omitempty"`
+	Results                  []sarifResult         `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifVersionControl struct {
+	RepositoryURI string `json:"repositoryUri"`
+	RevisionID    string `json:"revisionId,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   uint64 `json:"startLine"`
+	StartColumn int    `json:"startColumn,omitempty"`
+}
+
+// toSARIF renders the report as a SARIF 2.1.0 log. Problems in the CODEOWNERS file and rules that are dead or
+// shadowed are located at their line of the CODEOWNERS file, and files with no owners are located at the file.
+// Reports of a diff only describe the changed files, so dead and shadowed rules are left out of them.
+func (r *Report) toSARIF() (string, error) {
+	results := []sarifResult{}
+	for _, diagnostic := range r.Diagnostics {
+		results = append(results, sarifResult{
+			RuleID:    diagnostic.Code,
+			Level:     diagnostic.Severity,
+			Message:   sarifMessage{Text: diagnostic.Message},
+			Locations: newSARIFLocations(diagnostic.Path, &sarifRegion{StartLine: diagnostic.Line, StartColumn: diagnostic.Column}),
+		})
+	}
+	rules := r.Rules
+	if r.Diff != nil {
+		rules = nil
+	}
+	for _, rule := range rules {
+		region := &sarifRegion{StartLine: rule.LineNumber}
+		switch {
+		case rule.Dead():
+			results = append(results, sarifResult{
+				RuleID:    "dead-rule",
+				Level:     "warning",
+				Message:   sarifMessage{Text: fmt.Sprintf("pattern %q matches no files", rule.Pattern)},
+				Locations: newSARIFLocations(r.CodeownersPath, region),
+			})
+		case rule.Shadowed():
+			message := fmt.Sprintf("pattern %q matches %d files, but later rules decide the owners of all of them", rule.Pattern, rule.MatchedFilesCount)
+			if rule.ShadowedByLineNumber > 0 {
+				message = fmt.Sprintf("pattern %q matches %d files, but line %d decides the owners of all of them", rule.Pattern, rule.MatchedFilesCount, rule.ShadowedByLineNumber)
+			}
+			results = append(results, sarifResult{
+				RuleID:    "shadowed-rule",
+				Level:     "warning",
+				Message:   sarifMessage{Text: message},
+				Locations: newSARIFLocations(r.CodeownersPath, region),
+			})
+		}
+	}
+	for _, file := range r.Files {
+		if len(file.Owners) > 0 {
+			continue
+		}
+		message := "no CODEOWNERS rule matches this file"
+		if file.Rule != nil {
+			message = fmt.Sprintf("%s line %d (%s) assigns no owners to this file", r.CodeownersPath, file.Rule.LineNumber, file.Rule.Pattern)
+		}
+		results = append(results, sarifResult{
+			RuleID:    "unowned-file",
+			Level:     "warning",
+			Message:   sarifMessage{Text: message},
+			Locations: newSARIFLocations(file.Path, nil),
+		})
+	}
+
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "codeowners-coverage",
+			InformationURI: "https://github.com/aaronsky/codeowners-coverage",
+			Rules:          sarifRules,
+		}},
+		Results: results,
+	}
+	if uri, ok := remoteURI(r.RemoteURL); ok {
+		run.VersionControlProvenance = []sarifVersionControl{{RepositoryURI: uri, RevisionID: r.SHA}}
+	}
+
+	bytes, err := json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(bytes), nil
+}
+
+// newSARIFLocations locates a result at a path relative to the root of the repository, and optionally a region of it
+func newSARIFLocations(path string, region *sarifRegion) []sarifLocation {
+	return []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: path, URIBaseID: "%SRCROOT%"},
+		Region:           region,
+	}}}
+}
+
+// remoteURI converts a Git remote into an absolute URI, as required by SARIF. Remotes in the scp-like syntax
+// become ssh:// URIs, and remotes that are neither, such as local paths, cannot be converted.
+func remoteURI(remote string) (string, bool) {
+	if u, err := url.Parse(remote); err == nil && u.IsAbs() && (u.Host != "" || u.Scheme == "file") {
+		return u.String(), true
+	}
+	if match := scpLikeRemotePattern.FindStringSubmatch(remote); match != nil {
+		u := &url.URL{Scheme: "ssh", Host: match[2], Path: "/" + strings.TrimPrefix(match[3], "/")}
+		if match[1] != "" {
+			u.User = url.User(match[1])
+		}
+		return u.String(), true
+	}
+	return "", false
+}
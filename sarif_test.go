@@ -0,0 +1,93 @@
+package coverage
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestToFormatSARIF(t *testing.T) {
+	report := Report{
+		RemoteURL:      "git@github.com:org/repo.git",
+		SHA:            "abc123",
+		CodeownersPath: ".github/CODEOWNERS",
+		Diagnostics: []Diagnostic{
+			{Path: ".github/CODEOWNERS", Line: 1, Column: 6, Severity: "error", Code: "invalid-owner", Message: "\"frontend\" is not a valid owner"},
+		},
+		Files: []FileCoverage{
+			{Path: "src/main.go", Owners: []string{"@org/go"}, Rule: &RuleMatch{Pattern: "*.go", LineNumber: 4}},
+			{Path: "notes.txt", Owners: []string{}},
+		},
+		Rules: []RuleCoverage{
+			{Pattern: "*.rb", LineNumber: 2, MatchedFilesCount: 0},
+			{Pattern: "src/", LineNumber: 3, MatchedFilesCount: 1, DecidedFilesCount: 0, ShadowedByLineNumber: 4},
+			{Pattern: "*.go", LineNumber: 4, MatchedFilesCount: 1, DecidedFilesCount: 1},
+		},
+	}
+
+	output, err := report.ToFormat(ReportFormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log sarifLog
+	if err := json.Unmarshal([]byte(output), &log); err != nil {
+		t.Fatal(err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("expected a single SARIF 2.1.0 run, but got version %s with %d runs", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if len(run.VersionControlProvenance) != 1 || run.VersionControlProvenance[0].RevisionID != "abc123" {
+		t.Errorf("expected the commit to be recorded, but got %+v", run.VersionControlProvenance)
+	} else if uri := run.VersionControlProvenance[0].RepositoryURI; uri != "ssh://git@github.com/org/repo.git" {
+		t.Errorf("expected the remote to be recorded as an ssh:// URI, but got %s", uri)
+	}
+
+	expected := []struct {
+		ruleID string
+		uri    string
+		line   uint64
+	}{
+		{"invalid-owner", ".github/CODEOWNERS", 1},
+		{"dead-rule", ".github/CODEOWNERS", 2},
+		{"shadowed-rule", ".github/CODEOWNERS", 3},
+		{"unowned-file", "notes.txt", 0},
+	}
+	if len(run.Results) != len(expected) {
+		t.Fatalf("expected %d results, but there were %d", len(expected), len(run.Results))
+	}
+	for i, result := range run.Results {
+		location := result.Locations[0].PhysicalLocation
+		var line uint64
+		if location.Region != nil {
+			line = location.Region.StartLine
+		}
+		if result.RuleID != expected[i].ruleID || location.ArtifactLocation.URI != expected[i].uri || line != expected[i].line {
+			t.Errorf("expected result %d to be %s at %s:%d, but it was %s at %s:%d", i, expected[i].ruleID, expected[i].uri, expected[i].line, result.RuleID, location.ArtifactLocation.URI, line)
+		}
+	}
+}
+
+func TestRemoteURI(t *testing.T) {
+	cases := []struct {
+		remote string
+		uri    string
+	}{
+		{"https://github.com/org/repo.git", "https://github.com/org/repo.git"},
+		{"ssh://git@github.com/org/repo.git", "ssh://git@github.com/org/repo.git"},
+		{"file:///srv/git/repo.git", "file:///srv/git/repo.git"},
+		{"git@github.com:org/repo.git", "ssh://git@github.com/org/repo.git"},
+		{"github.com:org/repo.git", "ssh://github.com/org/repo.git"},
+		{"git@gitlab.example.com:/srv/repo.git", "ssh://git@gitlab.example.com/srv/repo.git"},
+		// remotes that cannot be converted are omitted
+		{"/srv/git/repo.git", ""},
+		{"../repo", ""},
+		{"", ""},
+	}
+
+	for _, c := range cases {
+		uri, ok := remoteURI(c.remote)
+		if ok != (c.uri != "") || uri != c.uri {
+			t.Errorf("expected %q to convert to %q, but got %q", c.remote, c.uri, uri)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// OwnerFiles is a group of files owned by the same owner, as returned by the groupByOwner template function
+type OwnerFiles struct {
+	// Owner is empty for the group of files with no owners
+	Owner string
+	Files []FileCoverage
+}
+
+// templateFuncs are the helper functions available to templates parsed by NewTemplateFormatter
+var templateFuncs = template.FuncMap{
+	"percent":      formatPercent,
+	"join":         func(sep string, values []string) string { return strings.Join(values, sep) },
+	"sortBy":       func(field string, list interface{}) (interface{}, error) { return sortByField(field, list, false) },
+	"sortDescBy":   func(field string, list interface{}) (interface{}, error) { return sortByField(field, list, true) },
+	"limit":        limitList,
+	"groupByOwner": groupByOwner,
+	"uncovered":    uncoveredFiles,
+	"directories":  flattenDirectories,
+}
+
+// NewTemplateFormatter parses a text/template that formats a Report. Besides the builtin functions of
+// text/template, templates can use:
+//
+//   - percent RATIO formats a ratio between 0 and 1 as a percentage, such as "87.5%"
+//   - join SEP LIST joins a list of strings, such as the owners of a file
+//   - sortBy FIELD LIST and sortDescBy FIELD LIST sort a copy of a list of structs by one of their fields
+//   - limit N LIST returns at most the first N elements of a list
+//   - groupByOwner FILES groups files by each of their owners, as a list of OwnerFiles sorted by owner
+//   - uncovered FILES returns the files with no owners
+//   - directories DIRECTORY flattens a directory and its descendants into a list, depth-first in path order
+//
+// For example, {{range limit 5 (sortDescBy "FilesCount" .Owners)}}{{.Owner}} {{percent .FilesRatio}}{{end}}
+// lists the five owners with the most files.
+func NewTemplateFormatter(text string) (Formatter, error) {
+	tmpl, err := template.New("report").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return FormatterFunc(func(w io.Writer, r *Report) error {
+		return tmpl.Execute(w, r)
+	}), nil
+}
+
+// sortByField returns a copy of a slice of structs, or pointers to structs, sorted by the named field
+func sortByField(field string, list interface{}, descending bool) (interface{}, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot sort %s, which is not a list", value.Kind())
+	}
+	sorted := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+	reflect.Copy(sorted, value)
+
+	for i := 0; i < sorted.Len(); i++ {
+		element := reflect.Indirect(sorted.Index(i))
+		if element.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("cannot sort by %s a list of %s", field, element.Kind())
+		}
+		if !element.FieldByName(field).IsValid() {
+			return nil, fmt.Errorf("cannot sort by %s, which is not a field of %s", field, element.Type())
+		}
+	}
+
+	var err error
+	result := sorted.Interface()
+	sort.SliceStable(result, func(i, j int) bool {
+		a := reflect.Indirect(sorted.Index(i)).FieldByName(field)
+		b := reflect.Indirect(sorted.Index(j)).FieldByName(field)
+		if descending {
+			a, b = b, a
+		}
+		less, lessErr := lessValue(a, b)
+		if lessErr != nil {
+			err = lessErr
+		}
+		return less
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lessValue compares two values of the same basic kind
+func lessValue(a, b reflect.Value) (bool, error) {
+	switch a.Kind() {
+	case reflect.String:
+		return a.String() < b.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() < b.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.Uint() < b.Uint(), nil
+	case reflect.Float32, reflect.Float64:
+		return a.Float() < b.Float(), nil
+	case reflect.Bool:
+		return !a.Bool() && b.Bool(), nil
+	default:
+		return false, fmt.Errorf("cannot sort by a field of kind %s", a.Kind())
+	}
+}
+
+// limitList returns at most the first n elements of a slice
+func limitList(n int, list interface{}) (interface{}, error) {
+	value := reflect.ValueOf(list)
+	if value.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot limit %s, which is not a list", value.Kind())
+	}
+	if n < 0 || n >= value.Len() {
+		return list, nil
+	}
+	return value.Slice(0, n).Interface(), nil
+}
+
+// groupByOwner groups files by each of their owners, sorted by owner, with files that have no owners grouped last
+func groupByOwner(files []FileCoverage) []OwnerFiles {
+	byOwner := map[string][]FileCoverage{}
+	var unowned []FileCoverage
+	for _, file := range files {
+		if len(file.Owners) == 0 {
+			unowned = append(unowned, file)
+		}
+		for _, owner := range uniqueStrings(file.Owners) {
+			byOwner[owner] = append(byOwner[owner], file)
+		}
+	}
+
+	groups := make([]OwnerFiles, 0, len(byOwner)+1)
+	for owner, files := range byOwner {
+		groups = append(groups, OwnerFiles{Owner: owner, Files: files})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Owner < groups[j].Owner
+	})
+	if len(unowned) > 0 {
+		groups = append(groups, OwnerFiles{Files: unowned})
+	}
+	return groups
+}
+
+// uncoveredFiles returns the files with no owners
+func uncoveredFiles(files []FileCoverage) []FileCoverage {
+	var uncovered []FileCoverage
+	for _, file := range files {
+		if len(file.Owners) == 0 {
+			uncovered = append(uncovered, file)
+		}
+	}
+	return uncovered
+}
+
+// flattenDirectories lists a directory and its descendants, depth-first in path order
+func flattenDirectories(dir *DirectoryCoverage) []*DirectoryCoverage {
+	var dirs []*DirectoryCoverage
+	if dir != nil {
+		dir.walk(func(d *DirectoryCoverage) {
+			dirs = append(dirs, d)
+		})
+	}
+	return dirs
+}
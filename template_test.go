@@ -0,0 +1,65 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTemplateFormatter(t *testing.T) {
+	files := []FileCoverage{
+		{Path: "src/main.go", Owners: []string{"@org/go", "@org/leads"}},
+		{Path: "src/util.go", Owners: []string{"@org/go"}},
+		{Path: "notes.txt", Owners: []string{}},
+	}
+	report := Report{
+		CoverageRatio: 2.0 / 3.0,
+		Files:         files,
+		Owners: []OwnerCoverage{
+			{Owner: "@org/leads", FilesCount: 1},
+			{Owner: "@org/go", FilesCount: 2},
+		},
+		Directories: newDirectoryCoverage(files, 0),
+	}
+
+	formatter, err := NewTemplateFormatter(`Coverage: {{percent .CoverageRatio}}
+{{range sortBy "Owner" .Owners}}{{.Owner}} {{end}}
+{{range limit 1 (sortDescBy "FilesCount" .Owners)}}top: {{.Owner}}{{end}}
+{{range groupByOwner .Files}}{{or .Owner "unowned"}}: {{range .Files}}{{.Path}} {{end}}
+{{end}}{{range uncovered .Files}}uncovered: {{.Path}}{{end}}
+{{range directories .Directories}}{{.Path}}={{.TotalFilesCount}} {{end}}
+{{join ", " (index .Files 0).Owners}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := formatter.Format(&b, &report); err != nil {
+		t.Fatal(err)
+	}
+	expected := `Coverage: 66.7%
+@org/go @org/leads 
+top: @org/go
+@org/go: src/main.go src/util.go 
+@org/leads: src/main.go 
+unowned: notes.txt 
+uncovered: notes.txt
+.=3 src=2 
+@org/go, @org/leads`
+	if b.String() != expected {
+		t.Errorf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+	if report.Owners[0].Owner != "@org/leads" {
+		t.Error("expected sorting to leave the report unchanged")
+	}
+}
+
+func TestTemplateSortByInvalidField(t *testing.T) {
+	formatter, err := NewTemplateFormatter(`{{range sortBy "Nope" .Owners}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Report{Owners: []OwnerCoverage{{Owner: "@org/go"}}}
+	if err := formatter.Format(&strings.Builder{}, &report); err == nil {
+		t.Error("expected sorting by a field that does not exist to fail")
+	}
+}
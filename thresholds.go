@@ -0,0 +1,98 @@
+package coverage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Thresholds are the minimum levels of coverage a Report is required to meet
+type Thresholds struct {
+	// MinCoverageRatio is the lowest acceptable CoverageRatio. 0 disables the check.
+	MinCoverageRatio float64
+	// MaxUncoveredFiles is the highest acceptable number of uncovered files. nil disables the check.
+	MaxUncoveredFiles *int
+	// MinDirectoryCoverageRatios maps slash-separated directories to the lowest acceptable coverage
+	// ratio of the files beneath them
+	MinDirectoryCoverageRatios map[string]float64
+}
+
+// CheckThresholds returns a description of each threshold the Report does not meet, or nil if it meets them all
+func (r *Report) CheckThresholds(thresholds Thresholds) []string {
+	var violations []string
+
+	if r.CoverageRatio < thresholds.MinCoverageRatio {
+		violations = append(violations, fmt.Sprintf("coverage ratio %.4f is below the minimum of %.4f", r.CoverageRatio, thresholds.MinCoverageRatio))
+	}
+
+	uncoveredFilesCount := r.TotalFilesCount - r.CoveredFilesCount
+	if thresholds.MaxUncoveredFiles != nil && uncoveredFilesCount > *thresholds.MaxUncoveredFiles {
+		violations = append(violations, fmt.Sprintf("%d files are uncovered, more than the maximum of %d", uncoveredFilesCount, *thresholds.MaxUncoveredFiles))
+	}
+
+	dirs := make([]string, 0, len(thresholds.MinDirectoryCoverageRatios))
+	for dir := range thresholds.MinDirectoryCoverageRatios {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		minRatio := thresholds.MinDirectoryCoverageRatios[dir]
+		covered, total := r.directoryCoverage(dir)
+		if total == 0 {
+			violations = append(violations, fmt.Sprintf("directory %s contains no files", dir))
+			continue
+		}
+		ratio := float64(covered) / float64(total)
+		if ratio < minRatio {
+			violations = append(violations, fmt.Sprintf("coverage ratio %.4f of directory %s is below the minimum of %.4f", ratio, dir, minRatio))
+		}
+	}
+
+	return violations
+}
+
+// directoryCoverage counts the covered and total files beneath the given slash-separated directory.
+// Files are counted directly, rather than from Directories, which may be limited in depth.
+func (r *Report) directoryCoverage(dir string) (covered int, total int) {
+	dir = strings.Trim(dir, "/")
+	for _, file := range r.Files {
+		if dir != "" && dir != "." && !strings.HasPrefix(file.Path, dir+"/") {
+			continue
+		}
+		total++
+		if len(file.Owners) > 0 {
+			covered++
+		}
+	}
+	return covered, total
+}
+
+// CheckBaseline returns a description of each file that regressed from the baseline report, or nil if none did.
+// A file regresses if it was covered in the baseline but is now uncovered, or if it is new and uncovered. Files
+// that were already uncovered in the baseline are tolerated. It fails if the baseline counted files but does not
+// list them, since every file would otherwise be treated as new.
+func (r *Report) CheckBaseline(baseline *Report) ([]string, error) {
+	if !baseline.listsFiles() {
+		return nil, fmt.Errorf("the baseline counts %d files, but does not list any of them", baseline.TotalFilesCount)
+	}
+
+	baselineCovered := map[string]bool{}
+	for _, file := range baseline.Files {
+		baselineCovered[file.Path] = len(file.Owners) > 0
+	}
+
+	var violations []string
+	for _, file := range r.Files {
+		if len(file.Owners) > 0 {
+			continue
+		}
+		covered, existed := baselineCovered[file.Path]
+		switch {
+		case !existed:
+			violations = append(violations, fmt.Sprintf("new file %s has no owners", file.Path))
+		case covered:
+			violations = append(violations, fmt.Sprintf("%s had owners in the baseline, but now has none", file.Path))
+		}
+	}
+	return violations, nil
+}
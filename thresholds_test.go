@@ -0,0 +1,113 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckThresholdsPass(t *testing.T) {
+	report := newThresholdsReport()
+	maxUncovered := 2
+	violations := report.CheckThresholds(Thresholds{
+		MinCoverageRatio:           0.5,
+		MaxUncoveredFiles:          &maxUncovered,
+		MinDirectoryCoverageRatios: map[string]float64{"services": 1, ".": 0.5},
+	})
+	if len(violations) != 0 {
+		t.Errorf("expected no violations, but there were %v", violations)
+	}
+}
+
+func TestCheckThresholdsFail(t *testing.T) {
+	report := newThresholdsReport()
+	maxUncovered := 1
+	violations := report.CheckThresholds(Thresholds{
+		MinCoverageRatio:           0.75,
+		MaxUncoveredFiles:          &maxUncovered,
+		MinDirectoryCoverageRatios: map[string]float64{"legacy/": 0.1, "missing": 0.5, "services": 1},
+	})
+	if len(violations) != 4 {
+		t.Fatalf("expected 4 violations, but there were %v", violations)
+	}
+	for i, expected := range []string{"coverage ratio 0.6000", "2 files are uncovered", "coverage ratio 0.0000 of directory legacy/", "directory missing contains no files"} {
+		if !strings.HasPrefix(violations[i], expected) {
+			t.Errorf("expected violation %d to start with %q, but it was %q", i, expected, violations[i])
+		}
+	}
+}
+
+func TestCheckThresholdsDisabled(t *testing.T) {
+	report := newThresholdsReport()
+	if violations := report.CheckThresholds(Thresholds{}); len(violations) != 0 {
+		t.Errorf("expected no violations when no thresholds are set, but there were %v", violations)
+	}
+}
+
+func newThresholdsReport() Report {
+	return Report{
+		CoveredFilesCount: 3,
+		TotalFilesCount:   5,
+		CoverageRatio:     0.6,
+		Files: []FileCoverage{
+			{Path: "README.md", Owners: []string{"@org/docs"}},
+			{Path: "services/api/main.go", Owners: []string{"@org/api"}},
+			{Path: "services/web/index.js", Owners: []string{"@org/web"}},
+			{Path: "legacy/old.c", Owners: []string{}},
+			{Path: "legacy/older.c", Owners: []string{}},
+		},
+	}
+}
+
+func TestCheckBaseline(t *testing.T) {
+	baseline := &Report{
+		Files: []FileCoverage{
+			{Path: "a.go", Owners: []string{"@org/go"}},
+			{Path: "b.go", Owners: []string{"@org/go"}},
+			{Path: "legacy.c", Owners: []string{}},
+		},
+	}
+	report := &Report{
+		Files: []FileCoverage{
+			{Path: "a.go", Owners: []string{"@org/go"}},
+			{Path: "b.go", Owners: []string{}},
+			{Path: "c.go", Owners: []string{}},
+			{Path: "d.go", Owners: []string{"@org/go"}},
+			{Path: "legacy.c", Owners: []string{}},
+		},
+	}
+
+	violations, err := report.CheckBaseline(baseline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"b.go had owners in the baseline, but now has none",
+		"new file c.go has no owners",
+	}
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations, but there were %v", len(expected), violations)
+	}
+	for i := range expected {
+		if violations[i] != expected[i] {
+			t.Errorf("expected violation %q, but it was %q", expected[i], violations[i])
+		}
+	}
+
+	if violations, err := baseline.CheckBaseline(baseline); err != nil || len(violations) != 0 {
+		t.Errorf("expected a report to meet its own baseline, but there were %v (%v)", violations, err)
+	}
+}
+
+func TestCheckBaselineWithoutFiles(t *testing.T) {
+	baseline := &Report{TotalFilesCount: 2, CoveredFilesCount: 1}
+	report := &Report{
+		TotalFilesCount: 1,
+		Files:           []FileCoverage{{Path: "a.go", Owners: []string{}}},
+	}
+	if _, err := report.CheckBaseline(baseline); err == nil {
+		t.Error("expected a baseline that does not list its files to be rejected")
+	}
+	if _, err := report.CheckBaseline(&Report{}); err != nil {
+		t.Errorf("expected an empty baseline to be accepted, but got %s", err)
+	}
+}